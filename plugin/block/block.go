@@ -1,8 +1,10 @@
 package block
 
 import (
+	"bytes"
 	"fmt"
-	"log"
+	"sort"
+	"strings"
 
 	"github.com/ipfs/go-cid"
 	"gitlab.com/c0b/go-ordered-json"
@@ -36,7 +38,12 @@ type IscnObject interface {
 	GetCid(string) (cid.Cid, error)
 	GetLink(string) (cid.Cid, string, error)
 
+	SetLinkSize(cid.Cid, uint64)
+
 	MarshalJSON() ([]byte, error)
+	MarshalDagJSON() ([]byte, error)
+	MarshalJSONLD(opts ...JSONLDOption) ([]byte, error)
+	ToDataModel() (map[string]interface{}, error)
 }
 
 // ==================================================
@@ -48,6 +55,7 @@ type Codec interface {
 	IscnObject
 
 	MarkNested()
+	SetMultihash(uint64, int)
 
 	GetData() map[string]interface{}
 	SetData(map[string]interface{}) error
@@ -64,6 +72,18 @@ type codecFactory map[uint64][]CodecFactoryFunc
 var factory codecFactory = codecFactory{}
 var schemaNames map[uint64]string = map[uint64]string{}
 
+// EncodeOption is an option for encoding the ISCN object
+type EncodeOption func(Codec)
+
+// WithMultihash sets the multihash function and length for the CID of the
+// ISCN object, the default length of the hash function is used if 'mhLength'
+// is -1
+func WithMultihash(mhType uint64, mhLength int) EncodeOption {
+	return func(obj Codec) {
+		obj.SetMultihash(mhType, mhLength)
+	}
+}
+
 // Validator is a validate function for post validation after set data in block
 type Validator func() error
 
@@ -78,13 +98,14 @@ func Encode(
 	codec uint64,
 	version uint64,
 	data map[string]interface{},
+	opts ...EncodeOption,
 ) (IscnObject, error) {
 	schemas, ok := factory[codec]
 	if !ok {
 		return nil, fmt.Errorf("%q is not registered", schemaNames[codec])
 	}
 
-	if version > (uint64)(len(schemas)) {
+	if version == 0 || version > (uint64)(len(schemas)) {
 		return nil, fmt.Errorf("<%s (v%d)> is not implemented", schemaNames[codec], version)
 	}
 	version--
@@ -94,6 +115,10 @@ func Encode(
 		return nil, err
 	}
 
+	for _, opt := range opts {
+		opt(obj)
+	}
+
 	if err := obj.SetData(data); err != nil {
 		return nil, err
 	}
@@ -110,47 +135,41 @@ func DecodeBlock(block blocks.Block) (node.Node, error) {
 	return Decode(block.RawData(), block.Cid())
 }
 
-// Decode decodes the raw IPLD data back to data object
+// Decode decodes the raw IPLD data back to data object. The hash of the data
+// is verified first, so the errors are distinguished as below:
+//
+//   - the hash of the data does not match the CID, i.e. the data is corrupted
+//   - *NonCanonicalError, i.e. the data is serialized differently
+//   - any other error, i.e. the data does not fit the schema
 func Decode(rawData []byte, c cid.Cid) (IscnObject, error) {
-	data := map[string]interface{}{}
-	if err := cbor.DecodeInto(rawData, &data); err != nil {
+	expected, err := c.Prefix().Sum(rawData)
+	if err != nil {
 		return nil, err
 	}
 
-	v, ok := data[ContextKey]
-	if !ok {
-		return nil, fmt.Errorf("Invalid ISCN IPLD object, missing context")
-	}
-
-	version, ok := v.(uint64)
-	if !ok {
-		return nil, fmt.Errorf("Context: 'uint64' is expected but '%T' is found", v)
-	}
-
-	schemas, ok := factory[c.Type()]
-	if !ok {
-		return nil, fmt.Errorf("%q is not registered", schemaNames[c.Type()])
-	}
-
-	if version > (uint64)(len(schemas)) {
-		return nil, fmt.Errorf("<%s (v%d)> is not implemented", schemaNames[c.Type()], version)
+	if !expected.Equals(c) {
+		return nil, fmt.Errorf("Cid %q: data is corrupted as the hash is not matched", c.String())
 	}
-	version--
 
-	obj, err := schemas[version]()
+	obj, err := decode(rawData, c.Type())
 	if err != nil {
 		return nil, err
 	}
 
-	if err := obj.Decode(data); err != nil {
-		return nil, err
-	}
+	// Use the same hash function as the CID to be verified
+	prefix := c.Prefix()
+	obj.SetMultihash(prefix.MhType, prefix.MhLength)
 
 	// Encode one more time to retrieve CID
 	if _, err := obj.Encode(); err != nil {
 		return nil, err
 	}
 
+	// Name the broken rule if the data is not serialized canonically
+	if err := CheckCanonical(rawData); err != nil {
+		return nil, err
+	}
+
 	// Verify the CID
 	if !obj.Cid().Equals(c) {
 		current, err := obj.Cid().StringOfBase('z')
@@ -169,6 +188,133 @@ func Decode(rawData []byte, c cid.Cid) (IscnObject, error) {
 	return obj, nil
 }
 
+// DecodeWithCodec decodes the raw IPLD data of 'codec' back to data object
+// without the CID. The data is verified by encoding it again, and the CID is
+// computed with SHA2-256 unless another hash function is given by the options
+func DecodeWithCodec(
+	rawData []byte,
+	codec uint64,
+	opts ...EncodeOption,
+) (IscnObject, cid.Cid, error) {
+	obj, err := decode(rawData, codec)
+	if err != nil {
+		return nil, cid.Undef, err
+	}
+
+	for _, opt := range opts {
+		opt(obj)
+	}
+
+	// Encode one more time to retrieve CID
+	if _, err := obj.Encode(); err != nil {
+		return nil, cid.Undef, err
+	}
+
+	// Name the broken rule if the data is not serialized canonically
+	if err := CheckCanonical(rawData); err != nil {
+		return nil, cid.Undef, err
+	}
+
+	// Verify the data
+	if !bytes.Equal(obj.RawData(), rawData) {
+		return nil, cid.Undef, fmt.Errorf("Data is not matched after encoding as <%s (v%d)>",
+			obj.GetName(), obj.GetVersion())
+	}
+
+	return obj, obj.Cid(), nil
+}
+
+// CodecMatch is the result of decoding the raw IPLD data with a codec
+type CodecMatch struct {
+	Codec  uint64
+	Object IscnObject
+	Cid    cid.Cid
+}
+
+// DecodeWithAnyCodec tries to decode the raw IPLD data with every registered
+// codec of ISCN object and returns the matched ones in order of codec
+func DecodeWithAnyCodec(rawData []byte, opts ...EncodeOption) []CodecMatch {
+	codecs := []uint64{}
+	for codec := range factory {
+		if IsIscnObject(codec) {
+			codecs = append(codecs, codec)
+		}
+	}
+	sort.Slice(codecs, func(i, j int) bool { return codecs[i] < codecs[j] })
+
+	res := []CodecMatch{}
+	for _, codec := range codecs {
+		obj, c, err := DecodeWithCodec(rawData, codec, opts...)
+		if err != nil {
+			continue
+		}
+
+		res = append(res, CodecMatch{
+			Codec:  codec,
+			Object: obj,
+			Cid:    c,
+		})
+	}
+
+	return res
+}
+
+// Convert encodes the ISCN object again with the schema 'version' of the same
+// codec, e.g. to switch between varint and native CBOR integers. The same hash
+// function is used and the linked objects are not converted
+func Convert(obj IscnObject, version uint64) (IscnObject, error) {
+	data, err := obj.ToDataModel()
+	if err != nil {
+		return nil, err
+	}
+	delete(data, ContextKey)
+
+	prefix := obj.Cid().Prefix()
+	return Encode(prefix.Codec, version, data, WithMultihash(prefix.MhType, prefix.MhLength))
+}
+
+// decode decodes the raw IPLD data with the schema of 'codec' and the version
+// specified by the context
+func decode(rawData []byte, codec uint64) (Codec, error) {
+	data := map[string]interface{}{}
+	if err := cbor.DecodeInto(rawData, &data); err != nil {
+		return nil, err
+	}
+
+	v, ok := data[ContextKey]
+	if !ok {
+		return nil, fmt.Errorf("Invalid ISCN IPLD object, missing context")
+	}
+
+	// The integer type decoded from CBOR depends on the version of refmt
+	context := NewContext("")
+	if err := context.Set(v); err != nil {
+		return nil, err
+	}
+	version := context.version
+
+	schemas, ok := factory[codec]
+	if !ok {
+		return nil, fmt.Errorf("%q is not registered", schemaNames[codec])
+	}
+
+	if version == 0 || version > (uint64)(len(schemas)) {
+		return nil, fmt.Errorf("<%s (v%d)> is not implemented", schemaNames[codec], version)
+	}
+	version--
+
+	obj, err := schemas[version]()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := obj.Decode(data); err != nil {
+		return nil, err
+	}
+
+	return obj, nil
+}
+
 // ==================================================
 // Base
 // ==================================================
@@ -186,8 +332,12 @@ type Base struct {
 	custom    map[string]interface{}
 	validator Validator
 
-	cid     *cid.Cid
-	rawData []byte
+	mhType   uint64
+	mhLength int
+
+	cid       *cid.Cid
+	rawData   []byte
+	linkSizes map[cid.Cid]uint64
 }
 
 var _ Codec = (*Base)(nil)
@@ -204,6 +354,8 @@ func NewBase(codec uint64, name string, version uint64, schema []Data) (*Base, e
 		keys:      []string{},
 		custom:    map[string]interface{}{},
 		validator: nil,
+		mhType:    mh.SHA2_256,
+		mhLength:  -1,
 	}
 
 	// Set "context" data
@@ -388,6 +540,11 @@ func (b *Base) GetLink(key string) (cid.Cid, string, error) {
 	return cid.Undef, "", fmt.Errorf("The value of %q is not a link", key)
 }
 
+// GetHandler returns the data handler of 'key'
+func (b *Base) GetHandler(key string) Data {
+	return b.data[key]
+}
+
 // SetValidator sets the validator function
 func (b *Base) SetValidator(validator Validator) {
 	b.validator = validator
@@ -417,18 +574,70 @@ func (b *Base) MarshalJSON() ([]byte, error) {
 		}
 	}
 
-	for key, value := range b.custom {
-		om.Set(key, value)
+	for _, key := range sortedKeys(b.custom) {
+		om.Set(key, jsonOfValue(b.custom[key]))
 	}
 
 	return om.MarshalJSON()
 }
 
+// ToDataModel returns the block as IPLD data model, i.e. links are Cid,
+// numbers are integers and bytes are byte slices
+func (b *Base) ToDataModel() (map[string]interface{}, error) {
+	m := map[string]interface{}{}
+	for _, key := range b.keys {
+		if !b.isPresent(key) {
+			continue
+		}
+
+		if err := b.data[key].ToDataModel(&m); err != nil {
+			return nil, err
+		}
+	}
+
+	for key, value := range b.custom {
+		m[key] = value
+	}
+
+	return m, nil
+}
+
+// MarshalDagJSON converts the block to DAG-JSON format
+func (b *Base) MarshalDagJSON() ([]byte, error) {
+	m, err := b.ToDataModel()
+	if err != nil {
+		return nil, err
+	}
+
+	buf := &bytes.Buffer{}
+	if err := writeDagJSON(buf, m); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
 // MarkNested marks the block as a nested block
 func (b *Base) MarkNested() {
 	b.isNested = true
 }
 
+// SetMultihash sets the multihash function and length for the CID of the block
+func (b *Base) SetMultihash(mhType uint64, mhLength int) {
+	b.mhType = mhType
+	b.mhLength = mhLength
+}
+
+// SetLinkSize sets the cumulative size of the linked block of CID 'c', e.g. the
+// result of Size of the linked block, which is reported by Links and counted
+// by Size and Stat
+func (b *Base) SetLinkSize(c cid.Cid, size uint64) {
+	if b.linkSizes == nil {
+		b.linkSizes = map[cid.Cid]uint64{}
+	}
+	b.linkSizes[c] = size
+}
+
 // GetData returns the block data as map[string]interface{}
 func (b *Base) GetData() map[string]interface{} {
 	res := map[string]interface{}{}
@@ -518,15 +727,17 @@ func (b *Base) Encode() (map[string]interface{}, error) {
 		m[key] = value
 	}
 
-	// CBOR-ise the data
+	// CBOR-ise the data as canonical CBOR, see CheckCanonical for the rules
 	rawData, err := cbor.DumpObject(m)
 	if err != nil {
 		return nil, err
 	}
 
-	c, err := cid.V1Builder{
-		Codec:  b.codec,
-		MhType: mh.SHA2_256,
+	c, err := cid.Prefix{
+		Version:  1,
+		Codec:    b.codec,
+		MhType:   b.mhType,
+		MhLength: b.mhLength,
 	}.Sum(rawData)
 	if err != nil {
 		return nil, err
@@ -631,35 +842,148 @@ func (b *Base) Resolve(path []string) (interface{}, []string, error) {
 		return data.Resolve(rest)
 	}
 
-	// TODO: custom parameters
+	if value, ok := b.custom[first]; ok {
+		return resolveValue(value, rest)
+	}
 
 	return nil, nil, fmt.Errorf("no such link")
 }
 
 // Tree lists all paths within the object under 'path', and up to the given depth.
 // To list the entire object (similar to `find .`) pass "" and -1
-func (*Base) Tree(path string, depth int) []string {
-	log.Println("Tree is not implemented")
-	return nil
+func (b *Base) Tree(path string, depth int) []string {
+	tree := []string{}
+	for _, key := range b.keys {
+		if !b.isPresent(key) {
+			continue
+		}
+
+		tree = append(tree, key)
+		for _, sub := range b.data[key].Tree() {
+			tree = append(tree, joinPath(key, sub))
+		}
+	}
+
+	for _, key := range sortedKeys(b.custom) {
+		tree = append(tree, key)
+		for _, sub := range treeOfValue(b.custom[key]) {
+			tree = append(tree, joinPath(key, sub))
+		}
+	}
+
+	path = strings.Trim(path, "/")
+	if path == "" && depth < 0 {
+		return tree
+	}
+
+	res := []string{}
+	for _, t := range tree {
+		sub := t
+		if path != "" {
+			if !strings.HasPrefix(t, path+"/") {
+				continue
+			}
+			sub = t[len(path)+1:]
+		}
+
+		if depth < 0 || len(strings.Split(sub, "/")) <= depth {
+			res = append(res, sub)
+		}
+	}
+
+	return res
+}
+
+// isPresent checks whether the property of 'key' exists in the block
+func (b *Base) isPresent(key string) bool {
+	if key == ContextKey {
+		// Nested block does not have context
+		return !b.isNested
+	}
+
+	_, exist := b.obj[key]
+	return exist
 }
 
 // node.Node interface
 
-// Copy will go away. It is here to comply with the Node interface.
-func (*Base) Copy() node.Node {
-	panic("dont use this yet")
+// Copy returns a deep copy of the block. The validator is not copied as it is
+// bound to the data handlers of the original block, the schema which embeds
+// the block should set a new one
+func (b *Base) Copy() node.Node {
+	data := map[string]Data{}
+	for key, handler := range b.data {
+		data[key] = handler.Copy()
+	}
+
+	keys := make([]string, len(b.keys))
+	copy(keys, b.keys)
+
+	// The nested objects decoded are shared between the data and the data
+	// handlers, so they are taken from the copied data handlers
+	var obj map[string]interface{}
+	if b.obj != nil {
+		obj = map[string]interface{}{}
+		for key, value := range b.obj {
+			if handler, ok := data[key]; ok {
+				obj[key] = copyDataValue(value, handler)
+			} else {
+				obj[key] = copyValue(value)
+			}
+		}
+	}
+
+	var c *cid.Cid
+	if b.cid != nil {
+		value := *b.cid
+		c = &value
+	}
+
+	var linkSizes map[cid.Cid]uint64
+	if b.linkSizes != nil {
+		linkSizes = map[cid.Cid]uint64{}
+		for key, value := range b.linkSizes {
+			linkSizes[key] = value
+		}
+	}
+
+	return &Base{
+		isNested:  b.isNested,
+		codec:     b.codec,
+		name:      b.name,
+		version:   b.version,
+		obj:       obj,
+		data:      data,
+		keys:      keys,
+		custom:    copyValue(b.custom).(map[string]interface{}),
+		validator: nil,
+		mhType:    b.mhType,
+		mhLength:  b.mhLength,
+		cid:       c,
+		rawData:   copyBytes(b.rawData),
+		linkSizes: linkSizes,
+	}
 }
 
 // Links is a helper function that returns all links within this object
 // HINT: Use `ipfs refs <cid>`
 func (b *Base) Links() []*node.Link {
 	links := []*node.Link{}
-	for _, d := range b.data {
-		switch c := d.(type) {
-		case *Cid:
-			if link, err := c.Link(); err == nil {
-				links = append(links, link)
-			}
+	for _, key := range b.keys {
+		if !b.isPresent(key) {
+			continue
+		}
+
+		links = append(links, prefixLinks(key, b.data[key].Links())...)
+	}
+
+	for _, key := range sortedKeys(b.custom) {
+		links = append(links, prefixLinks(key, linksOfValue(b.custom[key]))...)
+	}
+
+	for _, link := range links {
+		if size, ok := b.linkSizes[link.Cid]; ok {
+			link.Size = size
 		}
 	}
 	return links
@@ -679,12 +1003,40 @@ func (b *Base) ResolveLink(path []string) (*node.Link, []string, error) {
 	return nil, nil, fmt.Errorf("resolved item was not a link")
 }
 
-// Size will go away. It is here to comply with the Node interface.
-func (*Base) Size() (uint64, error) {
-	return 0, nil
+// Size returns the size of the encoded block plus the cumulative size of the
+// linked blocks. The size of a linked block is unknown to the block, so it is
+// only counted if it is set by SetLinkSize
+func (b *Base) Size() (uint64, error) {
+	size := uint64(len(b.rawData))
+	for _, link := range b.Links() {
+		size += link.Size
+	}
+	return size, nil
 }
 
-// Stat will go away. It is here to comply with the Node interface.
-func (*Base) Stat() (*node.NodeStat, error) {
-	return &node.NodeStat{}, nil
+// Stat returns the statistics of the block
+func (b *Base) Stat() (*node.NodeStat, error) {
+	if b.cid == nil {
+		return nil, fmt.Errorf("The block is not encoded yet")
+	}
+
+	links := b.Links()
+	linksSize := 0
+	for _, link := range links {
+		linksSize += len(link.Cid.Bytes())
+	}
+
+	cumulativeSize, err := b.Size()
+	if err != nil {
+		return nil, err
+	}
+
+	return &node.NodeStat{
+		Hash:           b.cid.String(),
+		NumLinks:       len(links),
+		BlockSize:      len(b.rawData),
+		LinksSize:      linksSize,
+		DataSize:       len(b.rawData) - linksSize,
+		CumulativeSize: int(cumulativeSize),
+	}, nil
 }
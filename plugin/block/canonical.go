@@ -0,0 +1,297 @@
+package block
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+)
+
+// ==================================================
+// Canonical CBOR
+// ==================================================
+
+// The ISCN objects are serialized by cbor.DumpObject as canonical CBOR, i.e.
+// the same data is always serialized to the same bytes and hence the same CID:
+//
+//   - Integers, lengths of strings, arrays and maps, and tags are encoded in
+//     the shortest form
+//   - Strings, arrays and maps are in definite length
+//   - Map keys are text strings without duplicates, sorted by length first
+//     and then bytewise (RFC 7049 section 3.9)
+//   - Floating point numbers are always encoded in 64 bits
+//   - The only tag is 42, i.e. the link of DAG-CBOR
+//   - The only simple values are false, true and null
+
+// CanonicalRule is a enum type for the rules of canonical CBOR
+type CanonicalRule int
+
+const (
+	// RuleMinimalEncoding requires integers, lengths and tags in the shortest form
+	RuleMinimalEncoding CanonicalRule = iota
+
+	// RuleDefiniteLength requires strings, arrays and maps in definite length
+	RuleDefiniteLength
+
+	// RuleMapKeyType requires map keys to be text strings
+	RuleMapKeyType
+
+	// RuleMapKeyOrder requires map keys sorted by length first and then bytewise
+	RuleMapKeyOrder
+
+	// RuleDuplicateMapKey requires map keys to be unique
+	RuleDuplicateMapKey
+
+	// RuleFloat64 requires floating point numbers in 64 bits
+	RuleFloat64
+
+	// RuleTag requires tag 42 to be the only tag
+	RuleTag
+
+	// RuleSimpleValue requires false, true and null to be the only simple values
+	RuleSimpleValue
+)
+
+// String returns the description of the rule
+func (r CanonicalRule) String() string {
+	switch r {
+	case RuleMinimalEncoding:
+		return "integers, lengths and tags should be encoded in the shortest form"
+	case RuleDefiniteLength:
+		return "strings, arrays and maps should be in definite length"
+	case RuleMapKeyType:
+		return "map keys should be text strings"
+	case RuleMapKeyOrder:
+		return "map keys should be sorted by length first and then bytewise"
+	case RuleDuplicateMapKey:
+		return "map keys should be unique"
+	case RuleFloat64:
+		return "floating point numbers should be encoded in 64 bits"
+	case RuleTag:
+		return "tag 42 should be the only tag"
+	case RuleSimpleValue:
+		return "false, true and null should be the only simple values"
+	}
+	return fmt.Sprintf("unknown rule %d", int(r))
+}
+
+// NonCanonicalError is the error of well-formed CBOR data which breaks a rule
+// of canonical CBOR
+type NonCanonicalError struct {
+	Rule   CanonicalRule
+	Offset int
+	Path   string
+}
+
+// Error returns the error message
+func (e *NonCanonicalError) Error() string {
+	path := e.Path
+	if path == "" {
+		path = "/"
+	}
+	return fmt.Sprintf("Non-canonical CBOR at offset %d (%s): %s", e.Offset, path, e.Rule)
+}
+
+// CheckCanonical checks whether the raw data is canonical CBOR. A
+// *NonCanonicalError is returned if the data is well-formed but breaks a rule,
+// any other error means the data is malformed
+func CheckCanonical(rawData []byte) error {
+	r := &canonicalReader{
+		data: rawData,
+	}
+
+	if err := r.walk(""); err != nil {
+		return err
+	}
+
+	if r.pos != len(r.data) {
+		return fmt.Errorf("Malformed CBOR at offset %d: unexpected trailing data", r.pos)
+	}
+
+	return nil
+}
+
+// ==================================================
+// canonicalReader
+// ==================================================
+
+const (
+	cborMajorUint   = 0
+	cborMajorNegint = 1
+	cborMajorBytes  = 2
+	cborMajorString = 3
+	cborMajorArray  = 4
+	cborMajorMap    = 5
+	cborMajorTag    = 6
+	cborMajorSimple = 7
+
+	cborTagLink = 42
+)
+
+// canonicalReader walks through CBOR data and checks the canonical rules
+type canonicalReader struct {
+	data []byte
+	pos  int
+}
+
+// nonCanonical creates the error of breaking 'rule' at 'offset'
+func (r *canonicalReader) nonCanonical(rule CanonicalRule, offset int, path string) error {
+	return &NonCanonicalError{
+		Rule:   rule,
+		Offset: offset,
+		Path:   path,
+	}
+}
+
+// malformed creates the error of malformed data at 'offset'
+func (r *canonicalReader) malformed(offset int, format string, a ...interface{}) error {
+	return fmt.Errorf("Malformed CBOR at offset %d: %s", offset, fmt.Sprintf(format, a...))
+}
+
+// read reads 'n' bytes
+func (r *canonicalReader) read(n uint64) ([]byte, error) {
+	if n > uint64(len(r.data)-r.pos) {
+		return nil, r.malformed(r.pos, "unexpected end of data")
+	}
+
+	res := r.data[r.pos : r.pos+int(n)]
+	r.pos += int(n)
+	return res, nil
+}
+
+// head reads the initial byte and the argument of a data item
+func (r *canonicalReader) head(path string) (byte, byte, uint64, error) {
+	offset := r.pos
+	b, err := r.read(1)
+	if err != nil {
+		return 0, 0, 0, err
+	}
+
+	major := b[0] >> 5
+	info := b[0] & 0x1f
+
+	var size uint64
+	var min uint64
+	switch {
+	case info < 24:
+		return major, info, uint64(info), nil
+	case info == 24:
+		size, min = 1, 24
+	case info == 25:
+		size, min = 2, 0x100
+	case info == 26:
+		size, min = 4, 0x10000
+	case info == 27:
+		size, min = 8, 0x100000000
+	case info == 31:
+		switch major {
+		case cborMajorBytes, cborMajorString, cborMajorArray, cborMajorMap:
+			return 0, 0, 0, r.nonCanonical(RuleDefiniteLength, offset, path)
+		}
+		return 0, 0, 0, r.malformed(offset, "unexpected indefinite length or break")
+	default:
+		return 0, 0, 0, r.malformed(offset, "reserved additional information %d", info)
+	}
+
+	arg, err := r.read(size)
+	if err != nil {
+		return 0, 0, 0, err
+	}
+
+	var value uint64
+	switch size {
+	case 1:
+		value = uint64(arg[0])
+	case 2:
+		value = uint64(binary.BigEndian.Uint16(arg))
+	case 4:
+		value = uint64(binary.BigEndian.Uint32(arg))
+	case 8:
+		value = binary.BigEndian.Uint64(arg)
+	}
+
+	// Floating point numbers and simple values are checked by the caller
+	if major != cborMajorSimple && value < min {
+		return 0, 0, 0, r.nonCanonical(RuleMinimalEncoding, offset, path)
+	}
+
+	return major, info, value, nil
+}
+
+// walk checks a data item and all the nested data items
+func (r *canonicalReader) walk(path string) error {
+	offset := r.pos
+	major, info, arg, err := r.head(path)
+	if err != nil {
+		return err
+	}
+
+	switch major {
+	case cborMajorUint, cborMajorNegint:
+		return nil
+
+	case cborMajorBytes, cborMajorString:
+		_, err := r.read(arg)
+		return err
+
+	case cborMajorArray:
+		for i := uint64(0); i < arg; i++ {
+			if err := r.walk(joinPath(path, fmt.Sprintf("%d", i))); err != nil {
+				return err
+			}
+		}
+		return nil
+
+	case cborMajorMap:
+		var prev []byte
+		for i := uint64(0); i < arg; i++ {
+			keyOffset := r.pos
+			keyMajor, _, keyLength, err := r.head(path)
+			if err != nil {
+				return err
+			}
+
+			if keyMajor != cborMajorString {
+				return r.nonCanonical(RuleMapKeyType, keyOffset, path)
+			}
+
+			key, err := r.read(keyLength)
+			if err != nil {
+				return err
+			}
+
+			if i != 0 {
+				if len(key) < len(prev) ||
+					(len(key) == len(prev) && bytes.Compare(key, prev) < 0) {
+					return r.nonCanonical(RuleMapKeyOrder, keyOffset, joinPath(path, string(key)))
+				}
+
+				if bytes.Equal(key, prev) {
+					return r.nonCanonical(RuleDuplicateMapKey, keyOffset, joinPath(path, string(key)))
+				}
+			}
+			prev = key
+
+			if err := r.walk(joinPath(path, string(key))); err != nil {
+				return err
+			}
+		}
+		return nil
+
+	case cborMajorTag:
+		if arg != cborTagLink {
+			return r.nonCanonical(RuleTag, offset, path)
+		}
+		return r.walk(path)
+
+	default: // cborMajorSimple
+		switch info {
+		case 20, 21, 22: // false, true and null
+			return nil
+		case 25, 26:
+			return r.nonCanonical(RuleFloat64, offset, path)
+		case 27:
+			return nil
+		}
+		return r.nonCanonical(RuleSimpleValue, offset, path)
+	}
+}
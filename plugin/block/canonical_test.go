@@ -0,0 +1,231 @@
+package block_test
+
+import (
+	"encoding/hex"
+	"errors"
+	"testing"
+
+	"github.com/ipfs/go-cid"
+	"github.com/likecoin/iscn-ipld/plugin/block"
+	"github.com/multiformats/go-multihash"
+)
+
+// The entity {"context": 1, "id": "a"} in canonical CBOR is
+// a2 6269 64 6161 67636f6e74657874 01
+const (
+	cborID      = "626964"
+	cborA       = "6161"
+	cborContext = "67636f6e74657874"
+)
+
+func TestCheckCanonical(t *testing.T) {
+	tests := []struct {
+		name      string
+		data      string
+		rule      *block.CanonicalRule
+		malformed bool
+	}{
+		{"canonical", "a2" + cborID + cborA + cborContext + "01", nil, false},
+		{"key order", "a2" + cborContext + "01" + cborID + cborA, rule(block.RuleMapKeyOrder), false},
+		{"duplicate key", "a2" + cborID + cborA + cborID + cborA, rule(block.RuleDuplicateMapKey), false},
+		{"key type", "a1" + "01" + cborA, rule(block.RuleMapKeyType), false},
+		{"minimal integer", "a2" + cborID + cborA + cborContext + "1801", rule(block.RuleMinimalEncoding), false},
+		{"minimal length", "a2" + cborID + "7801" + "61" + cborContext + "01", rule(block.RuleMinimalEncoding), false},
+		{"indefinite map", "bf" + cborID + cborA + "ff", rule(block.RuleDefiniteLength), false},
+		{"float32", "a1" + cborID + "fa3fc00000", rule(block.RuleFloat64), false},
+		{"float64", "a1" + cborID + "fb3ff8000000000000", nil, false},
+		{"other tag", "a1" + cborID + "c1" + "01", rule(block.RuleTag), false},
+		{"simple value", "a1" + cborID + "f7", rule(block.RuleSimpleValue), false},
+		{"trailing data", "a1" + cborID + cborA + "00", nil, true},
+		{"truncated", "a2" + cborID + cborA, nil, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			data, err := hex.DecodeString(test.data)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			err = block.CheckCanonical(data)
+			var nonCanonical *block.NonCanonicalError
+			isNonCanonical := errors.As(err, &nonCanonical)
+
+			switch {
+			case test.rule != nil:
+				if !isNonCanonical {
+					t.Fatalf("%q is expected but %v is returned", *test.rule, err)
+				}
+
+				if nonCanonical.Rule != *test.rule {
+					t.Fatalf("%q is expected but %q is returned", *test.rule, nonCanonical.Rule)
+				}
+			case test.malformed:
+				if err == nil || isNonCanonical {
+					t.Fatalf("an error of malformed data is expected but %v is returned", err)
+				}
+			default:
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func rule(r block.CanonicalRule) *block.CanonicalRule {
+	return &r
+}
+
+func TestEncodeIsCanonical(t *testing.T) {
+	holder := encode(t, block.CodecEntity, 1, map[string]interface{}{"id": "llc://holder"}).Cid()
+	tests := []struct {
+		name     string
+		codec    uint64
+		versions []uint64
+		data     map[string]interface{}
+	}{
+		{"entity", block.CodecEntity, []uint64{1}, map[string]interface{}{
+			"id":     "llc://alice",
+			"name":   "Alice",
+			"custom": map[string]interface{}{"long key": 1, "b": []interface{}{"x", 1.5}},
+		}},
+		{"content", block.CodecContent, []uint64{1, 2, 3}, map[string]interface{}{
+			"type":        "article",
+			"version":     1,
+			"fingerprint": "hash://sha256/abc",
+			"title":       "Title",
+			"tags":        []string{"a", "b"},
+		}},
+		{"rights", block.CodecRights, []uint64{1, 2}, map[string]interface{}{
+			"rights": []interface{}{
+				map[string]interface{}{
+					"holder": holder,
+					"type":   "License",
+					"terms":  holder,
+					"period": map[string]interface{}{"from": "2020-01-01T00:00:00Z"},
+				},
+			},
+		}},
+		{"stakeholders", block.CodecStakeholders, []uint64{1, 2, 3}, map[string]interface{}{
+			"stakeholders": []interface{}{
+				map[string]interface{}{"type": "Creator", "stakeholder": holder, "sharing": 1000},
+			},
+		}},
+	}
+
+	for _, test := range tests {
+		for _, version := range test.versions {
+			obj := encode(t, test.codec, version, test.data)
+			if err := block.CheckCanonical(obj.RawData()); err != nil {
+				t.Fatalf("%s (v%d): %s", test.name, version, err)
+			}
+		}
+	}
+}
+
+func TestDecode(t *testing.T) {
+	tests := []struct {
+		name      string
+		data      string
+		rule      *block.CanonicalRule
+		corrupted bool
+	}{
+		{"canonical", "a2" + cborID + cborA + cborContext + "01", nil, false},
+		{"key order", "a2" + cborContext + "01" + cborID + cborA, rule(block.RuleMapKeyOrder), false},
+		{"minimal integer", "a2" + cborID + cborA + cborContext + "1801", rule(block.RuleMinimalEncoding), false},
+		{"corrupted", "a2" + cborID + cborA + cborContext + "01", nil, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			data, err := hex.DecodeString(test.data)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			hashed := data
+			if test.corrupted {
+				hashed = []byte("corrupted")
+			}
+
+			c, err := cid.Prefix{
+				Version:  1,
+				Codec:    block.CodecEntity,
+				MhType:   multihash.SHA2_256,
+				MhLength: -1,
+			}.Sum(hashed)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			_, err = block.Decode(data, c)
+			var nonCanonical *block.NonCanonicalError
+			isNonCanonical := errors.As(err, &nonCanonical)
+
+			switch {
+			case test.rule != nil:
+				if !isNonCanonical || nonCanonical.Rule != *test.rule {
+					t.Fatalf("%q is expected but %v is returned", *test.rule, err)
+				}
+			case test.corrupted:
+				if err == nil || isNonCanonical {
+					t.Fatalf("an error of corrupted data is expected but %v is returned", err)
+				}
+			default:
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func TestDecodeNamesBrokenRule(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		rule *block.CanonicalRule
+	}{
+		{"canonical", "a2" + cborID + cborA + cborContext + "01", nil},
+		{"key order", "a2" + cborContext + "01" + cborID + cborA, rule(block.RuleMapKeyOrder)},
+		{"minimal integer", "a2" + cborID + cborA + cborContext + "1801", rule(block.RuleMinimalEncoding)},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			data, err := hex.DecodeString(test.data)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			c, err := cid.Prefix{
+				Version:  1,
+				Codec:    block.CodecEntity,
+				MhType:   multihash.SHA2_256,
+				MhLength: -1,
+			}.Sum(data)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			for _, decode := range []func() error{
+				func() error { _, err := block.Decode(data, c); return err },
+				func() error { _, _, err := block.DecodeWithCodec(data, block.CodecEntity); return err },
+			} {
+				err := decode()
+				if test.rule == nil {
+					if err != nil {
+						t.Fatal(err)
+					}
+					continue
+				}
+
+				var nonCanonical *block.NonCanonicalError
+				if !errors.As(err, &nonCanonical) || nonCanonical.Rule != *test.rule {
+					t.Fatalf("%q is expected but %v is returned", *test.rule, err)
+				}
+			}
+		})
+	}
+}
@@ -1,6 +1,15 @@
 package block
 
-import "fmt"
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+
+	"github.com/ipfs/go-cid"
+
+	node "github.com/ipfs/go-ipld-format"
+)
 
 // IPLD Codecs for ISCN
 // See the authoritative document:
@@ -47,3 +56,260 @@ func ValidateParent(version *Number, parent *Cid) error {
 
 	return nil
 }
+
+// joinPath joins the path of a property and the sub-path under it, the paths
+// are relative to the object without leading slash, e.g. "rights/0/type"
+func joinPath(path string, sub string) string {
+	if path == "" {
+		return sub
+	}
+	if sub == "" {
+		return path
+	}
+	return path + "/" + sub
+}
+
+// prefixLinks names the links under a property by their full paths
+func prefixLinks(path string, links []*node.Link) []*node.Link {
+	res := make([]*node.Link, 0, len(links))
+	for _, link := range links {
+		res = append(res, &node.Link{
+			Name: joinPath(path, link.Name),
+			Size: link.Size,
+			Cid:  link.Cid,
+		})
+	}
+	return res
+}
+
+// copyBytes creates a copy of a byte slice
+func copyBytes(b []byte) []byte {
+	if b == nil {
+		return nil
+	}
+
+	res := make([]byte, len(b))
+	copy(res, b)
+	return res
+}
+
+// copyValue creates a deep copy of a plain value, e.g. custom properties
+func copyValue(value interface{}) interface{} {
+	if value == nil {
+		return nil
+	}
+
+	if n, ok := value.(node.Node); ok {
+		return n.Copy()
+	}
+
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.Map:
+		if v.IsNil() {
+			return value
+		}
+
+		res := reflect.MakeMapWithSize(v.Type(), v.Len())
+		for _, k := range v.MapKeys() {
+			res.SetMapIndex(k, copyReflectValue(v.MapIndex(k), v.Type().Elem()))
+		}
+		return res.Interface()
+	case reflect.Slice:
+		if v.IsNil() {
+			return value
+		}
+
+		res := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+		for i := 0; i < v.Len(); i++ {
+			res.Index(i).Set(copyReflectValue(v.Index(i), v.Type().Elem()))
+		}
+		return res.Interface()
+	}
+
+	return value
+}
+
+// copyDataValue creates a copy of the value of a property with the copied
+// data handler of the property, the nested objects are taken from the data
+// handler instead of being copied again
+func copyDataValue(value interface{}, handler Data) interface{} {
+	switch d := handler.(type) {
+	case *Object:
+		if _, ok := value.(Codec); ok {
+			return d.object
+		}
+	case *DataArray:
+		if array, ok := value.([]interface{}); ok && len(array) == len(d.array) {
+			res := make([]interface{}, 0, len(array))
+			for i, elem := range array {
+				res = append(res, copyDataValue(elem, d.array[i]))
+			}
+			return res
+		}
+	}
+
+	return copyValue(value)
+}
+
+// copyReflectValue creates a deep copy of an element of a map or a slice
+func copyReflectValue(v reflect.Value, ty reflect.Type) reflect.Value {
+	if (v.Kind() == reflect.Interface || v.Kind() == reflect.Ptr) && v.IsNil() {
+		return v
+	}
+
+	res := reflect.ValueOf(copyValue(v.Interface()))
+	if !res.IsValid() {
+		return reflect.Zero(ty)
+	}
+	return res
+}
+
+// resolveValue resolves a path through a plain value, e.g. custom properties,
+// stopping at any link boundary. A CID or bytes which can be cast as a CID is
+// treated as a link
+func resolveValue(value interface{}, path []string) (interface{}, []string, error) {
+	switch v := value.(type) {
+	case cid.Cid:
+		return &node.Link{Cid: v}, path, nil
+	case []byte:
+		if c, err := cid.Cast(v); err == nil {
+			return &node.Link{Cid: c}, path, nil
+		}
+	}
+
+	if len(path) == 0 {
+		return value, nil, nil
+	}
+
+	first, rest := path[0], path[1:]
+	if value != nil {
+		v := reflect.ValueOf(value)
+		switch v.Kind() {
+		case reflect.Map:
+			if v.Type().Key().Kind() != reflect.String {
+				break
+			}
+
+			elem := v.MapIndex(reflect.ValueOf(first).Convert(v.Type().Key()))
+			if !elem.IsValid() {
+				return nil, nil, fmt.Errorf("no such link")
+			}
+
+			return resolveValue(elem.Interface(), rest)
+		case reflect.Slice, reflect.Array:
+			index, err := strconv.ParseUint(first, 10, 64)
+			if err != nil {
+				return nil, nil, fmt.Errorf("Unexpected path elements past %s", first)
+			}
+
+			if index >= uint64(v.Len()) {
+				return nil, nil, fmt.Errorf("index %d does not exist", index)
+			}
+
+			return resolveValue(v.Index(int(index)).Interface(), rest)
+		}
+	}
+
+	return nil, nil, fmt.Errorf("Unexpected path elements past %s", first)
+}
+
+// sortedKeys returns the keys of a map in sorted order
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// treeOfValue lists all paths under a plain value, e.g. custom properties
+func treeOfValue(value interface{}) []string {
+	res := []string{}
+	if value == nil {
+		return res
+	}
+
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.Map:
+		if v.Type().Key().Kind() != reflect.String {
+			return res
+		}
+
+		keys := []string{}
+		for _, k := range v.MapKeys() {
+			keys = append(keys, k.String())
+		}
+		sort.Strings(keys)
+
+		for _, key := range keys {
+			res = append(res, key)
+			elem := v.MapIndex(reflect.ValueOf(key).Convert(v.Type().Key()))
+			for _, sub := range treeOfValue(elem.Interface()) {
+				res = append(res, joinPath(key, sub))
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			// Bytes are treated as a plain value
+			return res
+		}
+
+		for i := 0; i < v.Len(); i++ {
+			index := strconv.Itoa(i)
+			res = append(res, index)
+			for _, sub := range treeOfValue(v.Index(i).Interface()) {
+				res = append(res, joinPath(index, sub))
+			}
+		}
+	}
+
+	return res
+}
+
+// linksOfValue lists the links under a plain value, e.g. custom properties,
+// named by their paths. A CID or bytes which can be cast as a CID is treated
+// as a link like resolveValue
+func linksOfValue(value interface{}) []*node.Link {
+	switch v := value.(type) {
+	case cid.Cid:
+		return []*node.Link{{Cid: v}}
+	case []byte:
+		if c, err := cid.Cast(v); err == nil {
+			return []*node.Link{{Cid: c}}
+		}
+		return []*node.Link{}
+	}
+
+	res := []*node.Link{}
+	if value == nil {
+		return res
+	}
+
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.Map:
+		if v.Type().Key().Kind() != reflect.String {
+			return res
+		}
+
+		keys := []string{}
+		for _, k := range v.MapKeys() {
+			keys = append(keys, k.String())
+		}
+		sort.Strings(keys)
+
+		for _, key := range keys {
+			elem := v.MapIndex(reflect.ValueOf(key).Convert(v.Type().Key()))
+			res = append(res, prefixLinks(key, linksOfValue(elem.Interface()))...)
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			res = append(res, prefixLinks(strconv.Itoa(i), linksOfValue(v.Index(i).Interface()))...)
+		}
+	}
+
+	return res
+}
@@ -11,86 +11,86 @@ const (
 
 // Register registers the schema of content block
 func Register() {
-	block.RegisterIscnObjectFactory(
+	block.RegisterSchemaSpecs(
 		block.CodecContent,
 		SchemaName,
-		[]block.CodecFactoryFunc{
-			newSchemaV1,
+		[]block.SchemaSpec{
+			schemaV1,
+			schemaV2,
+			schemaV3,
 		},
 	)
+
+	block.RegisterMigration(block.CodecContent, 1, block.MigrateAsIs)
+	block.RegisterMigration(block.CodecContent, 2, block.MigrateAsIs)
 }
 
 // ==================================================
-// base
+// schemaV1
 // ==================================================
 
-// base is the base struct for content (codec 0x0267)
-type base struct {
-	*block.Base
-}
-
-func newBase(version uint64, schema []block.Data) (*base, error) {
-	blockBase, err := block.NewBase(
-		block.CodecContent,
-		SchemaName,
-		version,
-		schema,
-	)
-	if err != nil {
-		return nil, err
-	}
-
-	return &base{
-		Base: blockBase,
-	}, nil
+// schemaV1 is the specification of content V1
+var schemaV1 = block.SchemaSpec{
+	Fields: []block.FieldSpec{
+		{Key: "type", Type: block.FieldString, Required: true},
+		{Key: "version", Type: block.FieldNumber, Required: true, Number: block.Uint64T},
+		{Key: "parent", Type: block.FieldLink, Codec: block.CodecContent},
+		{Key: "source", Type: block.FieldString}, // TODO URL
+		{Key: "edition", Type: block.FieldString},
+		{Key: "fingerprint", Type: block.FieldString, Required: true}, // TODO HashURL
+		{Key: "title", Type: block.FieldString, Required: true},
+		{Key: "description", Type: block.FieldString},
+		{Key: "tags", Type: block.FieldArray, Elem: &block.FieldSpec{Key: "_", Type: block.FieldString}},
+	},
+	Validate: validate,
 }
 
 // ==================================================
-// schemaV1
+// schemaV2
 // ==================================================
 
-// schemaV1 represents a content V1
-type schemaV1 struct {
-	*base
-
-	version *block.Number
-	parent  *block.Cid
+// schemaV2 is the specification of content V2, the links are encoded as CBOR
+// tag 42
+var schemaV2 = block.SchemaSpec{
+	Fields: []block.FieldSpec{
+		{Key: "type", Type: block.FieldString, Required: true},
+		{Key: "version", Type: block.FieldNumber, Required: true, Number: block.Uint64T},
+		{Key: "parent", Type: block.FieldLink, Codec: block.CodecContent, Tagged: true},
+		{Key: "source", Type: block.FieldString}, // TODO URL
+		{Key: "edition", Type: block.FieldString},
+		{Key: "fingerprint", Type: block.FieldString, Required: true}, // TODO HashURL
+		{Key: "title", Type: block.FieldString, Required: true},
+		{Key: "description", Type: block.FieldString},
+		{Key: "tags", Type: block.FieldArray, Elem: &block.FieldSpec{Key: "_", Type: block.FieldString}},
+	},
+	Validate: validate,
 }
 
-var _ block.IscnObject = (*schemaV1)(nil)
-
-func newSchemaV1() (block.Codec, error) {
-	version := block.NewNumber("version", true, block.Uint64T)
-	parent := block.NewCid("parent", false, block.CodecContent)
-
-	schema := []block.Data{
-		block.NewString("type", true),
-		version,
-		parent,
-		block.NewString("source", false), // TODO URL
-		block.NewString("edition", false),
-		block.NewString("fingerprint", true), // TODO HashURL
-		block.NewString("title", true),
-		block.NewString("description", false),
-		block.NewDataArray("tags", false, block.NewString("_", false)),
-	}
-
-	contentBase, err := newBase(1, schema)
-	if err != nil {
-		return nil, err
-	}
-
-	obj := schemaV1{
-		base:    contentBase,
-		version: version,
-		parent:  parent,
-	}
-	contentBase.SetValidator(obj.Validate)
+// ==================================================
+// schemaV3
+// ==================================================
 
-	return &obj, nil
+// schemaV3 is the specification of content V3, the links are encoded as CBOR
+// tag 42 and the numbers are encoded as CBOR integers
+var schemaV3 = block.SchemaSpec{
+	Fields: []block.FieldSpec{
+		{Key: "type", Type: block.FieldString, Required: true},
+		{Key: "version", Type: block.FieldNumber, Required: true, Number: block.Uint64T, Native: true},
+		{Key: "parent", Type: block.FieldLink, Codec: block.CodecContent, Tagged: true},
+		{Key: "source", Type: block.FieldString}, // TODO URL
+		{Key: "edition", Type: block.FieldString},
+		{Key: "fingerprint", Type: block.FieldString, Required: true}, // TODO HashURL
+		{Key: "title", Type: block.FieldString, Required: true},
+		{Key: "description", Type: block.FieldString},
+		{Key: "tags", Type: block.FieldArray, Elem: &block.FieldSpec{Key: "_", Type: block.FieldString}},
+	},
+	Validate: validate,
 }
 
-// Validate the data
-func (o *schemaV1) Validate() error {
-	return block.ValidateParent(o.version, o.parent)
+// validate the parent against the version
+func validate(b *block.Base) error {
+	return block.ValidateParent(
+		b.GetHandler("version").(*block.Number),
+		b.GetHandler("parent").(*block.Cid),
+	)
 }
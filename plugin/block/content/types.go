@@ -0,0 +1,77 @@
+package content
+
+import (
+	"github.com/ipfs/go-cid"
+	"github.com/likecoin/iscn-ipld/plugin/block"
+)
+
+// ==================================================
+// Content
+// ==================================================
+
+// Content is the typed struct of content, SchemaVersion is the schema version
+// to encode with. Tags is absent if it is nil
+type Content struct {
+	SchemaVersion uint64
+
+	Type        string
+	Version     uint64
+	Parent      cid.Cid
+	Source      *string
+	Edition     *string
+	Fingerprint string
+	Title       string
+	Description *string
+	Tags        []string
+
+	Custom map[string]interface{}
+}
+
+// FromObject reads the content from an ISCN object
+func FromObject(obj block.IscnObject) (*Content, error) {
+	f, err := block.ReadFields(obj, block.CodecContent)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &Content{
+		SchemaVersion: obj.GetVersion(),
+		Type:          f.String("type"),
+		Version:       f.Uint64("version"),
+		Parent:        f.Cid("parent"),
+		Source:        f.OptionalString("source"),
+		Edition:       f.OptionalString("edition"),
+		Fingerprint:   f.String("fingerprint"),
+		Title:         f.String("title"),
+		Description:   f.OptionalString("description"),
+		Tags:          f.Strings("tags"),
+	}
+	res.Custom = f.Custom()
+
+	if err := f.Err(); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// ToDataModel converts the content to IPLD data model without context
+func (c *Content) ToDataModel() map[string]interface{} {
+	data := block.NewDataModel(c.Custom)
+	data["type"] = c.Type
+	data["version"] = c.Version
+	block.SetLink(data, "parent", c.Parent)
+	block.SetOptionalString(data, "source", c.Source)
+	block.SetOptionalString(data, "edition", c.Edition)
+	data["fingerprint"] = c.Fingerprint
+	data["title"] = c.Title
+	block.SetOptionalString(data, "description", c.Description)
+	if c.Tags != nil {
+		data["tags"] = c.Tags
+	}
+	return data
+}
+
+// Encode encodes the content as an ISCN object
+func (c *Content) Encode(opts ...block.EncodeOption) (block.IscnObject, error) {
+	return block.Encode(block.CodecContent, c.SchemaVersion, c.ToDataModel(), opts...)
+}
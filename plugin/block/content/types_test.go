@@ -0,0 +1,101 @@
+package content_test
+
+import (
+	"testing"
+
+	"github.com/ipfs/go-cid"
+	"github.com/likecoin/iscn-ipld/plugin/block"
+	"github.com/likecoin/iscn-ipld/plugin/block/content"
+	"github.com/multiformats/go-multihash"
+)
+
+func TestContentRoundTrip(t *testing.T) {
+	content.Register()
+
+	parent, err := cid.Prefix{
+		Version:  1,
+		Codec:    block.CodecContent,
+		MhType:   multihash.SHA2_256,
+		MhLength: -1,
+	}.Sum([]byte("parent"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	description := "A description"
+	tests := []struct {
+		name    string
+		content content.Content
+		err     bool
+	}{
+		{"v1", content.Content{
+			SchemaVersion: 1,
+			Type:          "article",
+			Version:       1,
+			Fingerprint:   "hash://sha256/abc",
+			Title:         "Title",
+		}, false},
+		{"v2 with parent", content.Content{
+			SchemaVersion: 2,
+			Type:          "article",
+			Version:       2,
+			Parent:        parent,
+			Fingerprint:   "hash://sha256/abc",
+			Title:         "Title",
+			Description:   &description,
+			Tags:          []string{"a", "b"},
+		}, false},
+		{"v3 with empty tags", content.Content{
+			SchemaVersion: 3,
+			Type:          "article",
+			Version:       1,
+			Fingerprint:   "hash://sha256/abc",
+			Title:         "Title",
+			Tags:          []string{},
+			Custom:        map[string]interface{}{"extra": "value"},
+		}, false},
+		{"version 0", content.Content{
+			Type:        "article",
+			Version:     1,
+			Fingerprint: "hash://sha256/abc",
+			Title:       "Title",
+		}, true},
+		{"missing parent", content.Content{
+			SchemaVersion: 3,
+			Type:          "article",
+			Version:       2,
+			Fingerprint:   "hash://sha256/abc",
+			Title:         "Title",
+		}, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			obj, err := test.content.Encode()
+			if test.err {
+				if err == nil {
+					t.Fatalf("an error is expected but %s is returned", obj)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			res, err := content.FromObject(obj)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			again, err := res.Encode()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !again.Cid().Equals(obj.Cid()) {
+				t.Fatalf("CID %s is expected but %s is found", obj.Cid(), again.Cid())
+			}
+		})
+	}
+}
@@ -0,0 +1,109 @@
+package block
+
+import (
+	"testing"
+)
+
+func TestCopySharesNestedObjects(t *testing.T) {
+	elem := SchemaSpec{
+		Fields: []FieldSpec{
+			{Key: "name", Type: FieldString, Required: true},
+		},
+	}
+	elemPrototype := func() Codec {
+		res, _ := NewCodecFactory(CodecTimePeriod, "elem", 1, elem)()
+		return res
+	}
+
+	spec := SchemaSpec{
+		Fields: []FieldSpec{
+			{Key: "single", Type: FieldObject, Object: elemPrototype},
+			{Key: "items", Type: FieldArray, Elem: &FieldSpec{
+				Key:    "_",
+				Type:   FieldObject,
+				Object: elemPrototype,
+			}},
+		},
+	}
+
+	tests := []struct {
+		name string
+		data map[string]interface{}
+	}{
+		{"empty", map[string]interface{}{}},
+		{"single", map[string]interface{}{
+			"single": map[string]interface{}{"name": "a"},
+		}},
+		{"items", map[string]interface{}{
+			"items": []interface{}{
+				map[string]interface{}{"name": "a"},
+				map[string]interface{}{"name": "b"},
+			},
+		}},
+		{"custom", map[string]interface{}{
+			"single": map[string]interface{}{"name": "a"},
+			"extra":  []interface{}{"x"},
+		}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			obj, err := NewCodecFactory(CodecEntity, "copy", 1, spec)()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if err := obj.SetData(test.data); err != nil {
+				t.Fatal(err)
+			}
+
+			data, err := obj.Encode()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			decoded, err := NewCodecFactory(CodecEntity, "copy", 1, spec)()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if err := decoded.Decode(data); err != nil {
+				t.Fatal(err)
+			}
+
+			original := decoded.(*specObject).Base
+			copied := decoded.Copy().(*specObject).Base
+
+			if single, ok := copied.obj["single"]; ok {
+				if single != copied.data["single"].(*Object).object {
+					t.Fatal("the nested object is not the one of the data handler")
+				}
+
+				if single == original.obj["single"] {
+					t.Fatal("the nested object is not copied")
+				}
+			}
+
+			if items, ok := copied.obj["items"]; ok {
+				handler := copied.data["items"].(*DataArray)
+				for i, item := range items.([]interface{}) {
+					if item != handler.array[i].(*Object).object {
+						t.Fatalf("(Index %d) the nested object is not the one of the data handler", i)
+					}
+
+					if item == original.obj["items"].([]interface{})[i] {
+						t.Fatalf("(Index %d) the nested object is not copied", i)
+					}
+				}
+			}
+
+			if _, err := copied.Encode(); err != nil {
+				t.Fatal(err)
+			}
+
+			if !copied.Cid().Equals(obj.Cid()) {
+				t.Fatalf("CID %s is expected but %s is found", obj.Cid(), copied.Cid())
+			}
+		})
+	}
+}
@@ -0,0 +1,90 @@
+package block_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/likecoin/iscn-ipld/plugin/block"
+)
+
+func TestCopy(t *testing.T) {
+	holder := link(t, block.CodecEntity, "holder")
+	terms := link(t, block.CodecEntity, "terms")
+
+	tests := []struct {
+		name    string
+		codec   uint64
+		version uint64
+		data    map[string]interface{}
+		update  map[string]interface{}
+	}{
+		{"rights", block.CodecRights, 1, map[string]interface{}{
+			"rights": []interface{}{
+				map[string]interface{}{"holder": holder, "type": "License", "terms": terms},
+			},
+			"note": map[string]interface{}{"tags": []interface{}{"a"}},
+		}, map[string]interface{}{
+			"rights": []interface{}{
+				map[string]interface{}{"holder": terms, "type": "Copyright", "terms": holder},
+			},
+		}},
+		{"entity", block.CodecEntity, 1, map[string]interface{}{
+			"id":   "llc://entity",
+			"name": "Entity",
+		}, map[string]interface{}{
+			"id":   "llc://another",
+			"name": "Another",
+		}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			obj := encode(t, test.codec, test.version, test.data)
+			rawData := copyBytes(obj.RawData())
+
+			copied, ok := obj.Copy().(block.Codec)
+			if !ok {
+				t.Fatalf("Codec is expected but '%T' is found", obj.Copy())
+			}
+
+			if !copied.Cid().Equals(obj.Cid()) {
+				t.Fatalf("CID %s is expected but %s is found", obj.Cid(), copied.Cid())
+			}
+
+			if !bytes.Equal(copied.RawData(), rawData) {
+				t.Fatal("The raw data of the copy is different")
+			}
+
+			if err := copied.SetData(test.update); err != nil {
+				t.Fatal(err)
+			}
+
+			if _, err := copied.Encode(); err != nil {
+				t.Fatal(err)
+			}
+
+			if copied.Cid().Equals(obj.Cid()) {
+				t.Fatal("The copy is not updated")
+			}
+
+			if !bytes.Equal(obj.RawData(), rawData) {
+				t.Fatal("The original block is changed by its copy")
+			}
+
+			decoded, err := block.Decode(obj.RawData(), obj.Cid())
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !decoded.Cid().Equals(obj.Cid()) {
+				t.Fatalf("CID %s is expected but %s is found", obj.Cid(), decoded.Cid())
+			}
+		})
+	}
+}
+
+func copyBytes(data []byte) []byte {
+	res := make([]byte, len(data))
+	copy(res, data)
+	return res
+}
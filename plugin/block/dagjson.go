@@ -0,0 +1,120 @@
+package block
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strconv"
+
+	"github.com/ipfs/go-cid"
+)
+
+// writeDagJSON writes the value of IPLD data model in DAG-JSON format, i.e.
+// without whitespace, map keys are sorted, links are {"/": "<cid>"} and bytes
+// are {"/": {"bytes": "<base64>"}}
+func writeDagJSON(buf *bytes.Buffer, value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		buf.WriteString("null")
+		return nil
+	case cid.Cid:
+		if !v.Defined() {
+			return fmt.Errorf("DAG-JSON: undefined Cid")
+		}
+
+		buf.WriteString(`{"/":`)
+		writeDagJSONString(buf, v.String())
+		buf.WriteString("}")
+		return nil
+	case []byte:
+		buf.WriteString(`{"/":{"bytes":`)
+		writeDagJSONString(buf, base64.RawStdEncoding.EncodeToString(v))
+		buf.WriteString("}}")
+		return nil
+	case string:
+		writeDagJSONString(buf, v)
+		return nil
+	case bool:
+		buf.WriteString(strconv.FormatBool(v))
+		return nil
+	}
+
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		buf.WriteString(strconv.FormatInt(v.Int(), 10))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		buf.WriteString(strconv.FormatUint(v.Uint(), 10))
+	case reflect.Float32, reflect.Float64:
+		f := v.Float()
+		if math.IsInf(f, 0) || math.IsNaN(f) {
+			return fmt.Errorf("DAG-JSON: %v is not supported", f)
+		}
+
+		// Floats should be distinguishable from integers
+		s := strconv.FormatFloat(f, 'g', -1, 64)
+		if !bytes.ContainsAny([]byte(s), ".e") {
+			s += ".0"
+		}
+		buf.WriteString(s)
+	case reflect.Map:
+		if v.Type().Key().Kind() != reflect.String {
+			return fmt.Errorf("DAG-JSON: map key should be string but '%s' is found",
+				v.Type().Key())
+		}
+
+		keys := []string{}
+		for _, k := range v.MapKeys() {
+			keys = append(keys, k.String())
+		}
+		sort.Strings(keys)
+
+		buf.WriteString("{")
+		for i, key := range keys {
+			if i > 0 {
+				buf.WriteString(",")
+			}
+
+			writeDagJSONString(buf, key)
+			buf.WriteString(":")
+
+			elem := v.MapIndex(reflect.ValueOf(key).Convert(v.Type().Key()))
+			if err := writeDagJSON(buf, elem.Interface()); err != nil {
+				return err
+			}
+		}
+		buf.WriteString("}")
+	case reflect.Slice, reflect.Array:
+		buf.WriteString("[")
+		for i := 0; i < v.Len(); i++ {
+			if i > 0 {
+				buf.WriteString(",")
+			}
+
+			if err := writeDagJSON(buf, v.Index(i).Interface()); err != nil {
+				return err
+			}
+		}
+		buf.WriteString("]")
+	default:
+		return fmt.Errorf("DAG-JSON: '%T' is not supported", value)
+	}
+
+	return nil
+}
+
+// writeDagJSONString writes the string as JSON string without HTML escaping
+func writeDagJSONString(buf *bytes.Buffer, s string) {
+	encoder := json.NewEncoder(buf)
+	encoder.SetEscapeHTML(false)
+
+	// Encoding a string never fails
+	encoder.Encode(s)
+
+	// Remove the trailing newline written by the encoder
+	buf.Truncate(buf.Len() - 1)
+}
@@ -0,0 +1,85 @@
+package block_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/likecoin/iscn-ipld/plugin/block"
+)
+
+func TestMarshalDagJSON(t *testing.T) {
+	entity := link(t, block.CodecEntity, "entity")
+	rights := link(t, block.CodecRights, "rights")
+	stakeholders := link(t, block.CodecStakeholders, "stakeholders")
+	content := link(t, block.CodecContent, "content")
+
+	tests := []struct {
+		name     string
+		codec    uint64
+		version  uint64
+		data     map[string]interface{}
+		expected string
+	}{
+		{"sorted keys and custom values", block.CodecContent, 1, map[string]interface{}{
+			"type":        "article",
+			"version":     1,
+			"fingerprint": "hash://sha256/abc",
+			"title":       "<b>&</b>",
+			"extra": map[string]interface{}{
+				"z": 1.5,
+				"f": 2.0,
+				"a": []byte{1, 2, 3},
+				"l": entity,
+			},
+		}, fmt.Sprintf(`{"context":1,"extra":{"a":{"/":{"bytes":"AQID"}},"f":2.0,"l":{"/":"%s"},"z":1.5},`+
+			`"fingerprint":"hash://sha256/abc","title":"<b>&</b>","type":"article","version":1}`, entity)},
+		{"bytes and links", block.CodecISCN, 1, map[string]interface{}{
+			"id":           make([]byte, 32),
+			"timestamp":    "2020-01-01T00:00:00Z",
+			"version":      1,
+			"rights":       rights,
+			"stakeholders": stakeholders,
+			"content":      content,
+		}, fmt.Sprintf(`{"content":{"/":"%s"},"context":1,`+
+			`"id":{"/":{"bytes":"AAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAA"}},`+
+			`"rights":{"/":"%s"},"stakeholders":{"/":"%s"},"timestamp":"2020-01-01T00:00:00Z","version":1}`,
+			content, rights, stakeholders)},
+		{"nested objects", block.CodecRights, 1, map[string]interface{}{
+			"rights": []interface{}{
+				map[string]interface{}{
+					"holder": entity,
+					"type":   "License",
+					"terms":  entity,
+					"period": map[string]interface{}{"from": "2020-01-01T00:00:00Z"},
+				},
+			},
+		}, fmt.Sprintf(`{"context":1,"rights":[{"holder":{"/":"%s"},"period":{"from":"2020-01-01T00:00:00Z"},`+
+			`"terms":{"/":"%s"},"type":"License"}]}`, entity, entity)},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			obj := encode(t, test.codec, test.version, test.data)
+
+			res, err := obj.MarshalDagJSON()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if string(res) != test.expected {
+				t.Fatalf("%s is expected but %s is found", test.expected, res)
+			}
+
+			// The JSON output keeps the schema URL as context
+			j, err := obj.MarshalJSON()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !strings.Contains(string(j), `"context":"schema/`) {
+				t.Fatalf("the schema URL is expected in %s", j)
+			}
+		})
+	}
+}
@@ -3,10 +3,12 @@ package block
 import (
 	"bytes"
 	"encoding/binary"
+	"encoding/json"
 	"fmt"
 	"math"
 	"reflect"
 	"regexp"
+	"sort"
 	"strconv"
 
 	"github.com/ipfs/go-cid"
@@ -22,6 +24,7 @@ import (
 // Data is the interface for the data property handler
 type Data interface {
 	Prototype() Data
+	Copy() Data
 
 	IsRequired() bool
 	IsDefined() bool
@@ -32,8 +35,11 @@ type Data interface {
 	Encode(*map[string]interface{}) error
 	Decode(interface{}, *map[string]interface{}) error
 	ToJSON(*ordered.OrderedMap) error
+	ToDataModel(*map[string]interface{}) error
 
 	Resolve(path []string) (interface{}, []string, error)
+	Tree() []string
+	Links() []*node.Link
 }
 
 // ==================================================
@@ -65,6 +71,15 @@ func (b *DataBase) Prototype() *DataBase {
 	}
 }
 
+// Copy creates a deep copy of DataBase
+func (b *DataBase) Copy() *DataBase {
+	return &DataBase{
+		isRequired: b.isRequired,
+		isDefinded: b.isDefinded,
+		key:        b.key,
+	}
+}
+
 // IsRequired checks whether the data handler is required
 func (b *DataBase) IsRequired() bool {
 	return b.isRequired
@@ -92,6 +107,16 @@ func (b *DataBase) Decode(interface{}, *map[string]interface{}) error {
 	return nil
 }
 
+// Tree lists all paths under the data property, a plain value has none
+func (b *DataBase) Tree() []string {
+	return []string{}
+}
+
+// Links returns all links under the data property, a plain value has none
+func (b *DataBase) Links() []*node.Link {
+	return []*node.Link{}
+}
+
 // ==================================================
 // DataArray
 // ==================================================
@@ -124,6 +149,20 @@ func (d *DataArray) Prototype() Data {
 	}
 }
 
+// Copy creates a deep copy of DataArray
+func (d *DataArray) Copy() Data {
+	array := make([]Data, 0, len(d.array))
+	for _, data := range d.array {
+		array = append(array, data.Copy())
+	}
+
+	return &DataArray{
+		DataBase:  d.DataBase.Copy(),
+		array:     array,
+		prototype: d.prototype,
+	}
+}
+
 // Set the value of data handler array
 func (d *DataArray) Set(data interface{}) error {
 	switch reflect.TypeOf(data).Kind() {
@@ -197,6 +236,21 @@ func (d *DataArray) ToJSON(om *ordered.OrderedMap) error {
 	return nil
 }
 
+// ToDataModel prepares the data for IPLD data model
+func (d *DataArray) ToDataModel(m *map[string]interface{}) error {
+	placeholder := map[string]interface{}{}
+	res := []interface{}{}
+	for i, data := range d.array {
+		if err := data.ToDataModel(&placeholder); err != nil {
+			return fmt.Errorf("(Index %d) %s", i, err.Error())
+		}
+		res = append(res, placeholder[data.GetKey()])
+	}
+
+	(*m)[d.GetKey()] = res
+	return nil
+}
+
 // Resolve resolves the value
 func (d *DataArray) Resolve(path []string) (interface{}, []string, error) {
 	if len(path) == 0 {
@@ -224,6 +278,36 @@ func (d *DataArray) Resolve(path []string) (interface{}, []string, error) {
 	return d.array[index].Resolve(rest)
 }
 
+// JSONSchema describes an array of which the elements are the prototype
+func (d *DataArray) JSONSchema() *ordered.OrderedMap {
+	om := ordered.NewOrderedMap()
+	om.Set("type", "array")
+	om.Set("items", jsonSchemaOfHandler(d.prototype))
+	return om
+}
+
+// Tree lists all paths under the array
+func (d *DataArray) Tree() []string {
+	res := []string{}
+	for i, data := range d.array {
+		index := strconv.Itoa(i)
+		res = append(res, index)
+		for _, sub := range data.Tree() {
+			res = append(res, joinPath(index, sub))
+		}
+	}
+	return res
+}
+
+// Links returns all links under the array, named by their paths
+func (d *DataArray) Links() []*node.Link {
+	res := []*node.Link{}
+	for i, data := range d.array {
+		res = append(res, prefixLinks(strconv.Itoa(i), data.Links())...)
+	}
+	return res
+}
+
 // ==================================================
 // Object
 // ==================================================
@@ -265,6 +349,15 @@ func (d *Object) Prototype() Data {
 	}
 }
 
+// Copy creates a deep copy of Object
+func (d *Object) Copy() Data {
+	return &Object{
+		DataBase:      d.DataBase.Copy(),
+		prototypeFunc: d.prototypeFunc,
+		object:        d.object.Copy().(Codec),
+	}
+}
+
 // Set the value of Object
 func (d *Object) Set(data interface{}) error {
 	if value, ok := data.(map[string]interface{}); ok {
@@ -305,8 +398,25 @@ func (d *Object) Decode(data interface{}, m *map[string]interface{}) error {
 
 // ToJSON prepares the data for MarshalJSON
 func (d *Object) ToJSON(om *ordered.OrderedMap) error {
-	// TODO keep order of the JSON
-	om.Set(d.GetKey(), d.object.GetData())
+	// The nested object is marshalled by its data handlers to keep the order
+	// and the format of the values
+	obj, err := d.object.MarshalJSON()
+	if err != nil {
+		return err
+	}
+
+	om.Set(d.GetKey(), json.RawMessage(obj))
+	return nil
+}
+
+// ToDataModel prepares the data for IPLD data model
+func (d *Object) ToDataModel(m *map[string]interface{}) error {
+	obj, err := d.object.ToDataModel()
+	if err != nil {
+		return err
+	}
+
+	(*m)[d.GetKey()] = obj
 	return nil
 }
 
@@ -315,6 +425,24 @@ func (d *Object) Resolve(path []string) (interface{}, []string, error) {
 	return d.object.Resolve(path)
 }
 
+// JSONSchema describes the nested ISCN object
+func (d *Object) JSONSchema() *ordered.OrderedMap {
+	if n, ok := d.object.(jsonSchemaNode); ok {
+		return n.jsonSchema()
+	}
+	return ordered.NewOrderedMap()
+}
+
+// Tree lists all paths under the nested ISCN object
+func (d *Object) Tree() []string {
+	return d.object.Tree("", -1)
+}
+
+// Links returns all links under the nested ISCN object
+func (d *Object) Links() []*node.Link {
+	return d.object.Links()
+}
+
 // ==================================================
 // Number
 // ==================================================
@@ -340,8 +468,9 @@ const (
 type Number struct {
 	*DataBase
 
-	number []byte
-	ty     NumberType
+	number   []byte
+	ty       NumberType
+	isNative bool
 
 	i32 int32
 	u32 uint32
@@ -351,7 +480,8 @@ type Number struct {
 
 var _ Data = (*Number)(nil)
 
-// NewNumber creates a number data handler
+// NewNumber creates a number data handler, the number is encoded as varint
+// in bytes
 func NewNumber(key string, isRequired bool, ty NumberType) *Number {
 	return &Number{
 		DataBase: NewDataBase(key, isRequired),
@@ -359,11 +489,36 @@ func NewNumber(key string, isRequired bool, ty NumberType) *Number {
 	}
 }
 
+// NewNativeNumber creates a number data handler, the number is encoded as
+// CBOR integer
+func NewNativeNumber(key string, isRequired bool, ty NumberType) *Number {
+	return &Number{
+		DataBase: NewDataBase(key, isRequired),
+		ty:       ty,
+		isNative: true,
+	}
+}
+
 // Prototype creates a prototype Number
 func (d *Number) Prototype() Data {
 	return &Number{
 		DataBase: d.DataBase.Prototype(),
 		ty:       d.ty,
+		isNative: d.isNative,
+	}
+}
+
+// Copy creates a deep copy of Number
+func (d *Number) Copy() Data {
+	return &Number{
+		DataBase: d.DataBase.Copy(),
+		number:   copyBytes(d.number),
+		ty:       d.ty,
+		isNative: d.isNative,
+		i32:      d.i32,
+		u32:      d.u32,
+		i64:      d.i64,
+		u64:      d.u64,
 	}
 }
 
@@ -372,6 +527,11 @@ func (d *Number) GetType() NumberType {
 	return d.ty
 }
 
+// IsNative checks whether the number is encoded as CBOR integer
+func (d *Number) IsNative() bool {
+	return d.isNative
+}
+
 // GetInt32 returns an int32 value
 func (d *Number) GetInt32() (int32, error) {
 	if d.GetType() != Int32T {
@@ -406,6 +566,15 @@ func (d *Number) GetUint64() (uint64, error) {
 
 // Set the value of number
 func (d *Number) Set(data interface{}) error {
+	if err := d.setValue(data); err != nil {
+		return err
+	}
+
+	return d.DataBase.Set(data)
+}
+
+// setValue sets the value of number without marking it as set
+func (d *Number) setValue(data interface{}) error {
 	switch d.GetType() {
 	case Int32T:
 		var value int32
@@ -584,23 +753,60 @@ func (d *Number) Set(data interface{}) error {
 		d.u64 = value
 	}
 
-	return d.DataBase.Set(data)
+	return nil
 }
 
 // Encode Number
 func (d *Number) Encode(m *map[string]interface{}) error {
-	(*m)[d.GetKey()] = d.number
+	if !d.isNative {
+		(*m)[d.GetKey()] = d.number
+		return nil
+	}
+
+	// CBOR marshaller encodes integers as major type 0/1
+	switch d.GetType() {
+	case Int32T:
+		(*m)[d.GetKey()] = d.i32
+	case Uint32T:
+		(*m)[d.GetKey()] = d.u32
+	case Int64T:
+		(*m)[d.GetKey()] = d.i64
+	case Uint64T:
+		(*m)[d.GetKey()] = d.u64
+	}
 	return nil
 }
 
-// Decode Number
+// Decode Number, the number is expected as varint in bytes or as CBOR integer
+// according to the handler
 func (d *Number) Decode(data interface{}, m *map[string]interface{}) error {
+	if d.isNative {
+		if _, ok := data.([]byte); ok {
+			return fmt.Errorf("Number: %q is expected as CBOR integer but varint in bytes is found",
+				d.GetKey())
+		}
+
+		if err := d.setValue(data); err != nil {
+			return err
+		}
+
+		switch d.GetType() {
+		case Int32T:
+			(*m)[d.GetKey()] = d.i32
+		case Uint32T:
+			(*m)[d.GetKey()] = d.u32
+		case Int64T:
+			(*m)[d.GetKey()] = d.i64
+		case Uint64T:
+			(*m)[d.GetKey()] = d.u64
+		}
+		return d.DataBase.Decode(data, m)
+	}
+
 	number, ok := data.([]byte)
 	if !ok {
-		return fmt.Errorf("Unknown error during decoding number: "+
-			"'[]byte' is expected but '%T' is found",
-			data,
-		)
+		return fmt.Errorf("Number: %q is expected as varint in bytes but '%T' is found",
+			d.GetKey(), data)
 	}
 
 	var err error
@@ -655,6 +861,21 @@ func (d *Number) ToJSON(om *ordered.OrderedMap) error {
 	return nil
 }
 
+// ToDataModel prepares the data for IPLD data model
+func (d *Number) ToDataModel(m *map[string]interface{}) error {
+	switch d.GetType() {
+	case Int32T:
+		(*m)[d.GetKey()] = d.i32
+	case Uint32T:
+		(*m)[d.GetKey()] = d.u32
+	case Int64T:
+		(*m)[d.GetKey()] = d.i64
+	case Uint64T:
+		(*m)[d.GetKey()] = d.u64
+	}
+	return nil
+}
+
 // Resolve resolves the value
 func (d *Number) Resolve(path []string) (interface{}, []string, error) {
 	if len(path) != 0 {
@@ -675,6 +896,27 @@ func (d *Number) Resolve(path []string) (interface{}, []string, error) {
 	return nil, nil, fmt.Errorf("Number: unknown error")
 }
 
+// JSONSchema describes an integer within the range of the number type
+func (d *Number) JSONSchema() *ordered.OrderedMap {
+	om := ordered.NewOrderedMap()
+	om.Set("type", "integer")
+	switch d.GetType() {
+	case Int32T:
+		om.Set("minimum", math.MinInt32)
+		om.Set("maximum", math.MaxInt32)
+	case Uint32T:
+		om.Set("minimum", 0)
+		om.Set("maximum", uint32(math.MaxUint32))
+	case Int64T:
+		om.Set("minimum", int64(math.MinInt64))
+		om.Set("maximum", int64(math.MaxInt64))
+	case Uint64T:
+		om.Set("minimum", 0)
+		om.Set("maximum", uint64(math.MaxUint64))
+	}
+	return om
+}
+
 // ==================================================
 // String
 // ==================================================
@@ -717,6 +959,15 @@ func (d *String) Prototype() Data {
 	}
 }
 
+// Copy creates a deep copy of String
+func (d *String) Copy() Data {
+	return &String{
+		DataBase: d.DataBase.Copy(),
+		value:    d.value,
+		filter:   d.filter,
+	}
+}
+
 // Get returns the string value
 func (d *String) Get() string {
 	return d.value
@@ -760,6 +1011,12 @@ func (d *String) ToJSON(om *ordered.OrderedMap) error {
 	return nil
 }
 
+// ToDataModel prepares the data for IPLD data model
+func (d *String) ToDataModel(m *map[string]interface{}) error {
+	(*m)[d.GetKey()] = d.value
+	return nil
+}
+
 // Resolve resolves the value
 func (d *String) Resolve(path []string) (interface{}, []string, error) {
 	if len(path) != 0 {
@@ -769,6 +1026,21 @@ func (d *String) Resolve(path []string) (interface{}, []string, error) {
 	return d.value, nil, nil
 }
 
+// JSONSchema describes a string, the valid values are listed if filtered
+func (d *String) JSONSchema() *ordered.OrderedMap {
+	om := ordered.NewOrderedMap()
+	om.Set("type", "string")
+	if d.filter != nil {
+		values := make([]string, 0, len(*d.filter))
+		for value := range *d.filter {
+			values = append(values, value)
+		}
+		sort.Strings(values)
+		om.Set("enum", values)
+	}
+	return om
+}
+
 // ==================================================
 // Context
 // ==================================================
@@ -804,6 +1076,15 @@ func (d *Context) Prototype() Data {
 	}
 }
 
+// Copy creates a deep copy of Context
+func (d *Context) Copy() Data {
+	return &Context{
+		Number:  d.Number.Copy().(*Number),
+		schema:  d.schema,
+		version: d.version,
+	}
+}
+
 // Set the value of Context
 func (d *Context) Set(data interface{}) error {
 	err := d.Number.Set(data)
@@ -842,6 +1123,12 @@ func (d *Context) ToJSON(om *ordered.OrderedMap) error {
 	return nil
 }
 
+// ToDataModel prepares the data for IPLD data model
+func (d *Context) ToDataModel(m *map[string]interface{}) error {
+	(*m)[d.GetKey()] = d.version
+	return nil
+}
+
 // Resolve resolves the value
 func (d *Context) Resolve(path []string) (interface{}, []string, error) {
 	if len(path) != 0 {
@@ -852,10 +1139,33 @@ func (d *Context) Resolve(path []string) (interface{}, []string, error) {
 }
 
 func (d *Context) getSchemaURL() string {
+	return schemaURL(d.schema, d.version)
+}
+
+// schemaURL returns the schema URL of the schema 'version' of 'name'
+func schemaURL(name string, version uint64) string {
 	// TODO use the real schema path
-	return fmt.Sprintf("schema/%s-v%d", d.schema, d.version)
+	return fmt.Sprintf("schema/%s-v%d", name, version)
+}
+
+// ParseSchemaURL extracts the schema name and version from the schema URL of
+// a context
+func ParseSchemaURL(url string) (string, uint64, error) {
+	matches := schemaURLRegexp.FindStringSubmatch(url)
+	if matches == nil {
+		return "", 0, fmt.Errorf("Context: %q is not a valid schema URL", url)
+	}
+
+	version, err := strconv.ParseUint(matches[2], 10, 64)
+	if err != nil {
+		return "", 0, fmt.Errorf("Context: %s", err)
+	}
+
+	return matches[1], version, nil
 }
 
+var schemaURLRegexp = regexp.MustCompile(`^schema/([a-z]+)-v([0-9]+)$`)
+
 // ==================================================
 // Cid
 // ==================================================
@@ -864,13 +1174,14 @@ func (d *Context) getSchemaURL() string {
 type Cid struct {
 	*DataBase
 
-	codec uint64
-	c     []byte
+	codec    uint64
+	c        []byte
+	isTagged bool
 }
 
 var _ Data = (*Cid)(nil)
 
-// NewCid creates a IPFS CID data handler
+// NewCid creates a IPFS CID data handler, the CID is encoded as raw bytes
 func NewCid(key string, isRequired bool, codec uint64) *Cid {
 	return &Cid{
 		DataBase: NewDataBase(key, isRequired),
@@ -878,14 +1189,40 @@ func NewCid(key string, isRequired bool, codec uint64) *Cid {
 	}
 }
 
+// NewTaggedCid creates a IPFS CID data handler, the CID is encoded as CBOR
+// tag 42 which is the link of DAG-CBOR
+func NewTaggedCid(key string, isRequired bool, codec uint64) *Cid {
+	return &Cid{
+		DataBase: NewDataBase(key, isRequired),
+		codec:    codec,
+		isTagged: true,
+	}
+}
+
 // Prototype creates a prototype Cid
 func (d *Cid) Prototype() Data {
 	return &Cid{
 		DataBase: d.DataBase.Prototype(),
 		codec:    d.codec,
+		isTagged: d.isTagged,
+	}
+}
+
+// Copy creates a deep copy of Cid
+func (d *Cid) Copy() Data {
+	return &Cid{
+		DataBase: d.DataBase.Copy(),
+		codec:    d.codec,
+		c:        copyBytes(d.c),
+		isTagged: d.isTagged,
 	}
 }
 
+// IsTagged checks whether the CID is encoded as CBOR tag 42
+func (d *Cid) IsTagged() bool {
+	return d.isTagged
+}
+
 // Link returns a link object for IPLD
 func (d *Cid) Link() (*node.Link, error) {
 	_, c, err := cid.CidFromBytes(d.c)
@@ -915,16 +1252,41 @@ func (d *Cid) Set(data interface{}) error {
 
 // Encode Cid
 func (d *Cid) Encode(m *map[string]interface{}) error {
+	if d.isTagged {
+		_, c, err := cid.CidFromBytes(d.c)
+		if err != nil {
+			return err
+		}
+
+		// CBOR marshaller encodes Cid as tag 42
+		(*m)[d.GetKey()] = c
+		return nil
+	}
+
 	(*m)[d.GetKey()] = d.c
 	return nil
 }
 
-// Decode Cid
+// Decode Cid, only the form of the handler, i.e. raw bytes or CBOR tag 42, is
+// accepted
 func (d *Cid) Decode(data interface{}, m *map[string]interface{}) error {
-	c, ok := data.([]byte)
-	if !ok {
+	var c []byte
+	switch v := data.(type) {
+	case []byte:
+		if d.isTagged {
+			return fmt.Errorf("Cid: the link of %q is expected as CBOR tag 42 but raw bytes are found",
+				d.GetKey())
+		}
+		c = v
+	case cid.Cid:
+		if !d.isTagged {
+			return fmt.Errorf("Cid: the link of %q is expected as raw bytes but CBOR tag 42 is found",
+				d.GetKey())
+		}
+		c = v.Bytes()
+	default:
 		return fmt.Errorf("Unknown error during decoding Cid: "+
-			"'[]byte' is expected but '%T' is found",
+			"'[]byte' or 'cid.Cid' is expected but '%T' is found",
 			data,
 		)
 	}
@@ -966,6 +1328,17 @@ func (d *Cid) ToJSON(om *ordered.OrderedMap) error {
 	return nil
 }
 
+// ToDataModel prepares the data for IPLD data model
+func (d *Cid) ToDataModel(m *map[string]interface{}) error {
+	_, c, err := cid.CidFromBytes(d.c)
+	if err != nil {
+		return err
+	}
+
+	(*m)[d.GetKey()] = c
+	return nil
+}
+
 // Resolve resolves the link
 func (d *Cid) Resolve(path []string) (interface{}, []string, error) {
 	link, err := d.Link()
@@ -976,6 +1349,25 @@ func (d *Cid) Resolve(path []string) (interface{}, []string, error) {
 	return link, path, nil
 }
 
+// JSONSchema describes a link
+func (d *Cid) JSONSchema() *ordered.OrderedMap {
+	om := LinkJSONSchema()
+	if d.codec != 0 {
+		om.Set("description", fmt.Sprintf("Link to %s", schemaNames[d.codec]))
+	}
+	return om
+}
+
+// Links returns the link itself
+func (d *Cid) Links() []*node.Link {
+	link, err := d.Link()
+	if err != nil {
+		return []*node.Link{}
+	}
+
+	return []*node.Link{link}
+}
+
 // ==================================================
 // Timestamp
 // ==================================================
@@ -1014,6 +1406,14 @@ func (d *Timestamp) Prototype() Data {
 	}
 }
 
+// Copy creates a deep copy of Timestamp
+func (d *Timestamp) Copy() Data {
+	return &Timestamp{
+		DataBase: d.DataBase.Copy(),
+		ts:       d.ts,
+	}
+}
+
 // Set the value of timestamp string
 func (d *Timestamp) Set(data interface{}) error {
 	if ts, ok := data.(string); ok {
@@ -1071,6 +1471,12 @@ func (d *Timestamp) ToJSON(om *ordered.OrderedMap) error {
 	return nil
 }
 
+// ToDataModel prepares the data for IPLD data model
+func (d *Timestamp) ToDataModel(m *map[string]interface{}) error {
+	(*m)[d.GetKey()] = d.ts
+	return nil
+}
+
 // Resolve resolves the value
 func (d *Timestamp) Resolve(path []string) (interface{}, []string, error) {
 	if len(path) != 0 {
@@ -1079,3 +1485,11 @@ func (d *Timestamp) Resolve(path []string) (interface{}, []string, error) {
 
 	return d.ts, nil, nil
 }
+
+// JSONSchema describes a string matching TimestampPattern
+func (d *Timestamp) JSONSchema() *ordered.OrderedMap {
+	om := ordered.NewOrderedMap()
+	om.Set("type", "string")
+	om.Set("pattern", TimestampPattern)
+	return om
+}
@@ -0,0 +1,90 @@
+package block_test
+
+import (
+	"testing"
+
+	"github.com/likecoin/iscn-ipld/plugin/block"
+	"github.com/multiformats/go-multihash"
+)
+
+func TestDecodeWithCodec(t *testing.T) {
+	entity := encode(t, block.CodecEntity, 1, map[string]interface{}{"id": "llc://alice"})
+	blake2b := []block.EncodeOption{block.WithMultihash(multihash.BLAKE2B_MIN+31, -1)}
+	entityBlake2b, err := block.Encode(block.CodecEntity, 1, map[string]interface{}{"id": "llc://alice"}, blake2b...)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		rawData  []byte
+		codec    uint64
+		opts     []block.EncodeOption
+		expected block.IscnObject
+	}{
+		{"entity", entity.RawData(), block.CodecEntity, nil, entity},
+		{"entity BLAKE2b-256", entity.RawData(), block.CodecEntity, blake2b, entityBlake2b},
+		{"wrong codec", entity.RawData(), block.CodecContent, nil, nil},
+		{"unregistered codec", entity.RawData(), 0x71, nil, nil},
+		{"malformed", []byte{0xa1}, block.CodecEntity, nil, nil},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			obj, c, err := block.DecodeWithCodec(test.rawData, test.codec, test.opts...)
+			if test.expected == nil {
+				if err == nil {
+					t.Fatalf("an error is expected but %s is returned", obj)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !c.Equals(test.expected.Cid()) || !obj.Cid().Equals(c) {
+				t.Fatalf("%s is expected but %s is found", test.expected.Cid(), c)
+			}
+		})
+	}
+}
+
+func TestDecodeWithAnyCodec(t *testing.T) {
+	entity := encode(t, block.CodecEntity, 1, map[string]interface{}{"id": "llc://alice"})
+	content := encode(t, block.CodecContent, 1, map[string]interface{}{
+		"type":        "article",
+		"version":     1,
+		"fingerprint": "hash://sha256/abc",
+		"title":       "Hello",
+	})
+
+	tests := []struct {
+		name    string
+		rawData []byte
+		codecs  []uint64
+	}{
+		{"entity", entity.RawData(), []uint64{block.CodecEntity}},
+		{"content", content.RawData(), []uint64{block.CodecContent}},
+		{"none", []byte{0xa0}, []uint64{}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			matches := block.DecodeWithAnyCodec(test.rawData)
+			if len(matches) != len(test.codecs) {
+				t.Fatalf("%d matches are expected but %d are found", len(test.codecs), len(matches))
+			}
+
+			for i, match := range matches {
+				if match.Codec != test.codecs[i] || match.Cid.Type() != test.codecs[i] {
+					t.Fatalf("%#x is expected but %#x is found", test.codecs[i], match.Codec)
+				}
+
+				if !match.Object.Cid().Equals(match.Cid) {
+					t.Fatalf("%s is expected but %s is found", match.Cid, match.Object.Cid())
+				}
+			}
+		})
+	}
+}
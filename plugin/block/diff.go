@@ -0,0 +1,217 @@
+package block
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gitlab.com/c0b/go-ordered-json"
+)
+
+// ==================================================
+// Diff
+// ==================================================
+
+// ChangeType is a enum type for the type of a change
+type ChangeType int
+
+const (
+	// ChangeAdded represents a field or an element added
+	ChangeAdded ChangeType = iota
+
+	// ChangeRemoved represents a field or an element removed
+	ChangeRemoved
+
+	// ChangeModified represents a value changed
+	ChangeModified
+)
+
+// String returns the name of the change type
+func (t ChangeType) String() string {
+	switch t {
+	case ChangeAdded:
+		return "added"
+	case ChangeRemoved:
+		return "removed"
+	case ChangeModified:
+		return "changed"
+	}
+	return fmt.Sprintf("unknown change %d", int(t))
+}
+
+// Change is a field-level change between two ISCN objects, the values are in
+// IPLD data model. 'Old' is nil for an added field and 'New' is nil for a
+// removed field
+type Change struct {
+	Path string
+	Type ChangeType
+	Old  interface{}
+	New  interface{}
+}
+
+// Changes is the list of changes between two ISCN objects in order of path
+type Changes []Change
+
+// Diff compares two ISCN objects of the same codec, e.g. two versions of a
+// kernel, and returns the field-level changes from 'old' to 'new'. The
+// elements of arrays such as rights and stakeholders are compared by index
+func Diff(old IscnObject, new IscnObject) (Changes, error) {
+	if old.Cid().Type() != new.Cid().Type() {
+		return nil, fmt.Errorf("Diff: <%s> and <%s> are not the same codec",
+			old.GetName(), new.GetName())
+	}
+
+	oldData, err := old.ToDataModel()
+	if err != nil {
+		return nil, err
+	}
+
+	newData, err := new.ToDataModel()
+	if err != nil {
+		return nil, err
+	}
+
+	res := Changes{}
+	if err := diffValue("", oldData, newData, &res); err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// diffValue compares two values in IPLD data model recursively
+func diffValue(path string, old interface{}, new interface{}, res *Changes) error {
+	switch o := old.(type) {
+	case map[string]interface{}:
+		if n, ok := new.(map[string]interface{}); ok {
+			keys := sortedKeys(o)
+			for _, key := range sortedKeys(n) {
+				if _, ok := o[key]; !ok {
+					keys = append(keys, key)
+				}
+			}
+			sort.Strings(keys)
+
+			for _, key := range keys {
+				sub := joinPath(path, key)
+				oldValue, inOld := o[key]
+				newValue, inNew := n[key]
+
+				switch {
+				case !inOld:
+					*res = append(*res, Change{Path: sub, Type: ChangeAdded, New: newValue})
+				case !inNew:
+					*res = append(*res, Change{Path: sub, Type: ChangeRemoved, Old: oldValue})
+				default:
+					if err := diffValue(sub, oldValue, newValue, res); err != nil {
+						return err
+					}
+				}
+			}
+			return nil
+		}
+
+	case []interface{}:
+		if n, ok := new.([]interface{}); ok {
+			for i := 0; i < len(o) || i < len(n); i++ {
+				sub := joinPath(path, strconv.Itoa(i))
+
+				switch {
+				case i >= len(o):
+					*res = append(*res, Change{Path: sub, Type: ChangeAdded, New: n[i]})
+				case i >= len(n):
+					*res = append(*res, Change{Path: sub, Type: ChangeRemoved, Old: o[i]})
+				default:
+					if err := diffValue(sub, o[i], n[i], res); err != nil {
+						return err
+					}
+				}
+			}
+			return nil
+		}
+	}
+
+	// Values are compared in DAG-JSON, so the integer types do not matter
+	oldJSON, err := dagJSONOfValue(old)
+	if err != nil {
+		return err
+	}
+
+	newJSON, err := dagJSONOfValue(new)
+	if err != nil {
+		return err
+	}
+
+	if !bytes.Equal(oldJSON, newJSON) {
+		*res = append(*res, Change{Path: path, Type: ChangeModified, Old: old, New: new})
+	}
+
+	return nil
+}
+
+// dagJSONOfValue converts a value in IPLD data model to DAG-JSON
+func dagJSONOfValue(value interface{}) ([]byte, error) {
+	buf := &bytes.Buffer{}
+	if err := writeDagJSON(buf, value); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// MarshalJSON converts the changes to JSON, the values are in DAG-JSON format
+func (c Changes) MarshalJSON() ([]byte, error) {
+	res := make([]*ordered.OrderedMap, 0, len(c))
+	for _, change := range c {
+		om := ordered.NewOrderedMap()
+		om.Set("path", change.Path)
+		om.Set("type", change.Type.String())
+
+		if change.Type != ChangeAdded {
+			value, err := dagJSONOfValue(change.Old)
+			if err != nil {
+				return nil, err
+			}
+			om.Set("old", json.RawMessage(value))
+		}
+
+		if change.Type != ChangeRemoved {
+			value, err := dagJSONOfValue(change.New)
+			if err != nil {
+				return nil, err
+			}
+			om.Set("new", json.RawMessage(value))
+		}
+
+		res = append(res, om)
+	}
+
+	return json.Marshal(res)
+}
+
+// String renders the changes in human-readable text, one change per line
+func (c Changes) String() string {
+	value := func(v interface{}) string {
+		res, err := dagJSONOfValue(v)
+		if err != nil {
+			return fmt.Sprintf("<%s>", err)
+		}
+		return string(res)
+	}
+
+	var sb strings.Builder
+	for _, change := range c {
+		switch change.Type {
+		case ChangeAdded:
+			fmt.Fprintf(&sb, "+ %s: %s\n", change.Path, value(change.New))
+		case ChangeRemoved:
+			fmt.Fprintf(&sb, "- %s: %s\n", change.Path, value(change.Old))
+		case ChangeModified:
+			fmt.Fprintf(&sb, "~ %s: %s -> %s\n", change.Path, value(change.Old), value(change.New))
+		}
+	}
+
+	return sb.String()
+}
@@ -0,0 +1,111 @@
+package block_test
+
+import (
+	"testing"
+
+	"github.com/likecoin/iscn-ipld/plugin/block"
+)
+
+func TestDiff(t *testing.T) {
+	holder := encode(t, block.CodecEntity, 1, map[string]interface{}{"id": "llc://holder"}).Cid()
+	terms := encode(t, block.CodecEntity, 1, map[string]interface{}{"id": "llc://terms"}).Cid()
+	right := func(ty string) map[string]interface{} {
+		return map[string]interface{}{"holder": holder, "type": ty, "terms": terms}
+	}
+
+	tests := []struct {
+		name    string
+		old     map[string]interface{}
+		new     map[string]interface{}
+		changes []block.Change
+	}{
+		{
+			"same",
+			map[string]interface{}{"rights": []interface{}{right("License")}},
+			map[string]interface{}{"rights": []interface{}{right("License")}},
+			[]block.Change{},
+		},
+		{
+			"modified",
+			map[string]interface{}{"rights": []interface{}{right("License")}},
+			map[string]interface{}{"rights": []interface{}{right("Copyright")}},
+			[]block.Change{
+				{Path: "rights/0/type", Type: block.ChangeModified, Old: "License", New: "Copyright"},
+			},
+		},
+		{
+			"added",
+			map[string]interface{}{"rights": []interface{}{right("License")}},
+			map[string]interface{}{"rights": []interface{}{right("License"), right("Copyright")}, "note": "x"},
+			[]block.Change{
+				{Path: "note", Type: block.ChangeAdded},
+				{Path: "rights/1", Type: block.ChangeAdded},
+			},
+		},
+		{
+			"removed",
+			map[string]interface{}{"rights": []interface{}{right("License"), right("Copyright")}},
+			map[string]interface{}{"rights": []interface{}{right("License")}},
+			[]block.Change{
+				{Path: "rights/1", Type: block.ChangeRemoved},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			old := encode(t, block.CodecRights, 2, test.old)
+			new := encode(t, block.CodecRights, 2, test.new)
+
+			changes, err := block.Diff(old, new)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if len(changes) != len(test.changes) {
+				t.Fatalf("%d changes are expected but %v is found", len(test.changes), changes)
+			}
+
+			tree := map[string]struct{}{}
+			for _, path := range old.Tree("", -1) {
+				tree[path] = struct{}{}
+			}
+			for _, path := range new.Tree("", -1) {
+				tree[path] = struct{}{}
+			}
+
+			for i, change := range changes {
+				expected := test.changes[i]
+				if change.Path != expected.Path || change.Type != expected.Type {
+					t.Fatalf("(Change %d) %s %s is expected but %s %s is found",
+						i, expected.Path, expected.Type, change.Path, change.Type)
+				}
+
+				if expected.Type == block.ChangeModified &&
+					(change.Old != expected.Old || change.New != expected.New) {
+					t.Fatalf("(Change %d) %v -> %v is expected but %v -> %v is found",
+						i, expected.Old, expected.New, change.Old, change.New)
+				}
+
+				// The paths of changes are the same as the paths of Tree
+				if _, ok := tree[change.Path]; !ok {
+					t.Fatalf("(Change %d) %q is not a path of Tree", i, change.Path)
+				}
+			}
+		})
+	}
+}
+
+func TestDiffOfOtherCodec(t *testing.T) {
+	entity := encode(t, block.CodecEntity, 1, map[string]interface{}{"id": "llc://alice"})
+	content := encode(t, block.CodecContent, 3, map[string]interface{}{
+		"type":        "article",
+		"version":     1,
+		"fingerprint": "hash://sha256/abc",
+		"title":       "Title",
+	})
+
+	if _, err := block.Diff(entity, content); err == nil {
+		t.Fatal("an error is expected for different codecs")
+	}
+}
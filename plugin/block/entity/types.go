@@ -0,0 +1,56 @@
+package entity
+
+import (
+	"github.com/likecoin/iscn-ipld/plugin/block"
+)
+
+// ==================================================
+// Entity
+// ==================================================
+
+// Entity is the typed struct of an entity, SchemaVersion is the schema version
+// to encode with
+type Entity struct {
+	SchemaVersion uint64
+
+	ID          string
+	Name        *string
+	Description *string
+
+	Custom map[string]interface{}
+}
+
+// FromObject reads the entity from an ISCN object
+func FromObject(obj block.IscnObject) (*Entity, error) {
+	f, err := block.ReadFields(obj, block.CodecEntity)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &Entity{
+		SchemaVersion: obj.GetVersion(),
+		ID:            f.String("id"),
+		Name:          f.OptionalString("name"),
+		Description:   f.OptionalString("description"),
+	}
+	res.Custom = f.Custom()
+
+	if err := f.Err(); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// ToDataModel converts the entity to IPLD data model without context
+func (e *Entity) ToDataModel() map[string]interface{} {
+	data := block.NewDataModel(e.Custom)
+	data["id"] = e.ID
+	block.SetOptionalString(data, "name", e.Name)
+	block.SetOptionalString(data, "description", e.Description)
+	return data
+}
+
+// Encode encodes the entity as an ISCN object
+func (e *Entity) Encode(opts ...block.EncodeOption) (block.IscnObject, error) {
+	return block.Encode(block.CodecEntity, e.SchemaVersion, e.ToDataModel(), opts...)
+}
@@ -0,0 +1,58 @@
+package entity_test
+
+import (
+	"testing"
+
+	"github.com/likecoin/iscn-ipld/plugin/block/entity"
+)
+
+func TestEntityRoundTrip(t *testing.T) {
+	entity.Register()
+
+	name := "Alice"
+	tests := []struct {
+		name   string
+		entity entity.Entity
+		err    bool
+	}{
+		{"minimal", entity.Entity{SchemaVersion: 1, ID: "llc://alice"}, false},
+		{"name", entity.Entity{SchemaVersion: 1, ID: "llc://alice", Name: &name}, false},
+		{"custom", entity.Entity{
+			SchemaVersion: 1,
+			ID:            "llc://alice",
+			Custom:        map[string]interface{}{"extra": "value"},
+		}, false},
+		{"version 0", entity.Entity{ID: "llc://alice"}, true},
+		{"not implemented", entity.Entity{SchemaVersion: 2, ID: "llc://alice"}, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			obj, err := test.entity.Encode()
+			if test.err {
+				if err == nil {
+					t.Fatalf("an error is expected but %s is returned", obj)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			res, err := entity.FromObject(obj)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			again, err := res.Encode()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !again.Cid().Equals(obj.Cid()) {
+				t.Fatalf("CID %s is expected but %s is found", obj.Cid(), again.Cid())
+			}
+		})
+	}
+}
@@ -0,0 +1,288 @@
+package block
+
+import (
+	"fmt"
+
+	"github.com/ipfs/go-cid"
+)
+
+// ==================================================
+// Fields
+// ==================================================
+
+// Fields reads the fields of an ISCN object in IPLD data model, e.g. the
+// result of ToDataModel, into typed values. An absent field is read as the
+// zero value and the first unexpected value is kept as the error
+type Fields struct {
+	data map[string]interface{}
+	read map[string]struct{}
+	err  error
+}
+
+// NewFields creates a reader of the fields of 'data'
+func NewFields(data map[string]interface{}) *Fields {
+	return &Fields{
+		data: data,
+		read: map[string]struct{}{ContextKey: {}},
+	}
+}
+
+// Err returns the first error found when reading the fields
+func (f *Fields) Err() error {
+	return f.err
+}
+
+// value returns the value of 'key' and marks it as read
+func (f *Fields) value(key string) (interface{}, bool) {
+	f.read[key] = struct{}{}
+	value, ok := f.data[key]
+	return value, ok && value != nil
+}
+
+// fail keeps the first error
+func (f *Fields) fail(key string, expected string, value interface{}) {
+	if f.err == nil {
+		f.err = fmt.Errorf("The value of %q is not '%s' but '%T'", key, expected, value)
+	}
+}
+
+// String reads the value of 'key' as string
+func (f *Fields) String(key string) string {
+	if res := f.OptionalString(key); res != nil {
+		return *res
+	}
+	return ""
+}
+
+// OptionalString reads the value of 'key' as string, nil if it is absent
+func (f *Fields) OptionalString(key string) *string {
+	value, ok := f.value(key)
+	if !ok {
+		return nil
+	}
+
+	res, ok := value.(string)
+	if !ok {
+		f.fail(key, "string", value)
+		return nil
+	}
+
+	return &res
+}
+
+// Bytes reads the value of 'key' as byte slice
+func (f *Fields) Bytes(key string) []byte {
+	value, ok := f.value(key)
+	if !ok {
+		return nil
+	}
+
+	res, ok := value.([]byte)
+	if !ok {
+		f.fail(key, "[]byte", value)
+		return nil
+	}
+
+	return res
+}
+
+// Uint32 reads the value of 'key' as uint32
+func (f *Fields) Uint32(key string) uint32 {
+	value, ok := f.value(key)
+	if !ok {
+		return 0
+	}
+
+	res, ok := value.(uint32)
+	if !ok {
+		f.fail(key, "uint32", value)
+		return 0
+	}
+
+	return res
+}
+
+// Uint64 reads the value of 'key' as uint64
+func (f *Fields) Uint64(key string) uint64 {
+	value, ok := f.value(key)
+	if !ok {
+		return 0
+	}
+
+	res, ok := value.(uint64)
+	if !ok {
+		f.fail(key, "uint64", value)
+		return 0
+	}
+
+	return res
+}
+
+// Cid reads the value of 'key' as CID, cid.Undef if it is absent
+func (f *Fields) Cid(key string) cid.Cid {
+	value, ok := f.value(key)
+	if !ok {
+		return cid.Undef
+	}
+
+	res, ok := value.(cid.Cid)
+	if !ok {
+		f.fail(key, "Cid", value)
+		return cid.Undef
+	}
+
+	return res
+}
+
+// Link reads the value of 'key' as a link which can be a CID or a URL
+func (f *Fields) Link(key string) (cid.Cid, *string) {
+	value, ok := f.value(key)
+	if !ok {
+		return cid.Undef, nil
+	}
+
+	switch v := value.(type) {
+	case cid.Cid:
+		return v, nil
+	case string:
+		return cid.Undef, &v
+	}
+
+	f.fail(key, "link", value)
+	return cid.Undef, nil
+}
+
+// Strings reads the value of 'key' as string slice, nil if it is absent
+func (f *Fields) Strings(key string) []string {
+	array := f.array(key)
+	if array == nil {
+		return nil
+	}
+
+	res := make([]string, 0, len(array))
+	for i, elem := range array {
+		value, ok := elem.(string)
+		if !ok {
+			f.fail(fmt.Sprintf("%s/%d", key, i), "string", elem)
+			return nil
+		}
+		res = append(res, value)
+	}
+
+	return res
+}
+
+// Object reads the value of 'key' as nested object, nil if it is absent
+func (f *Fields) Object(key string) map[string]interface{} {
+	value, ok := f.value(key)
+	if !ok {
+		return nil
+	}
+
+	res, ok := value.(map[string]interface{})
+	if !ok {
+		f.fail(key, "map[string]interface{}", value)
+		return nil
+	}
+
+	return res
+}
+
+// Objects reads the value of 'key' as an array of nested objects, nil if it
+// is absent
+func (f *Fields) Objects(key string) []map[string]interface{} {
+	array := f.array(key)
+	if array == nil {
+		return nil
+	}
+
+	res := make([]map[string]interface{}, 0, len(array))
+	for i, elem := range array {
+		value, ok := elem.(map[string]interface{})
+		if !ok {
+			f.fail(fmt.Sprintf("%s/%d", key, i), "map[string]interface{}", elem)
+			return nil
+		}
+		res = append(res, value)
+	}
+
+	return res
+}
+
+// array reads the value of 'key' as array, nil if it is absent
+func (f *Fields) array(key string) []interface{} {
+	value, ok := f.value(key)
+	if !ok {
+		return nil
+	}
+
+	res, ok := value.([]interface{})
+	if !ok {
+		f.fail(key, "[]interface{}", value)
+		return nil
+	}
+
+	return res
+}
+
+// Custom returns the fields which are not read, except the context, nil if
+// there is none
+func (f *Fields) Custom() map[string]interface{} {
+	var res map[string]interface{}
+	for key, value := range f.data {
+		if _, ok := f.read[key]; ok {
+			continue
+		}
+
+		if res == nil {
+			res = map[string]interface{}{}
+		}
+		res[key] = value
+	}
+
+	return res
+}
+
+// ==================================================
+// Typed structs
+// ==================================================
+
+// SetOptionalString sets 'key' of 'data' to the string if it is not nil
+func SetOptionalString(data map[string]interface{}, key string, value *string) {
+	if value != nil {
+		data[key] = *value
+	}
+}
+
+// SetLink sets 'key' of 'data' to the CID if it is defined
+func SetLink(data map[string]interface{}, key string, value cid.Cid) {
+	if value.Defined() {
+		data[key] = value
+	}
+}
+
+// NewDataModel creates the data of an ISCN object in IPLD data model with the
+// custom fields, the fields of the schema should be set afterwards
+func NewDataModel(custom map[string]interface{}) map[string]interface{} {
+	data := map[string]interface{}{}
+	for key, value := range custom {
+		data[key] = value
+	}
+	return data
+}
+
+// ReadFields checks the codec of the ISCN object and returns its data in IPLD
+// data model for reading into a typed struct
+func ReadFields(obj IscnObject, codec uint64) (*Fields, error) {
+	if obj.Cid().Type() != codec {
+		return nil, fmt.Errorf("<%s> is expected but <%s> is found",
+			schemaNames[codec], obj.GetName())
+	}
+
+	data, err := obj.ToDataModel()
+	if err != nil {
+		return nil, err
+	}
+
+	return NewFields(data), nil
+}
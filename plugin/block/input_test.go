@@ -0,0 +1,80 @@
+package block_test
+
+import (
+	"testing"
+
+	"github.com/likecoin/iscn-ipld/plugin/block"
+	"github.com/multiformats/go-multihash"
+
+	cbor "github.com/ipfs/go-ipld-cbor"
+)
+
+// The CBOR input parsers of the plugin decode the input by DecodeWithCodec
+func TestCBORInput(t *testing.T) {
+	content := func(m map[string]interface{}) map[string]interface{} {
+		data := map[string]interface{}{
+			block.ContextKey: uint64(1),
+			"type":           "article",
+			"version":        []byte{1}, // varint
+			"fingerprint":    "hash://sha256/abc",
+			"title":          "Hello",
+		}
+		for key, value := range m {
+			if value == nil {
+				delete(data, key)
+				continue
+			}
+			data[key] = value
+		}
+		return data
+	}
+
+	dump := func(data interface{}) []byte {
+		rawData, err := cbor.DumpObject(data)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return rawData
+	}
+
+	tests := []struct {
+		name    string
+		codec   uint64
+		rawData []byte
+		err     bool
+	}{
+		{"valid", block.CodecContent, dump(content(nil)), false},
+		{"custom property", block.CodecContent, dump(content(map[string]interface{}{"note": "x"})), false},
+		{"arbitrary bytes", block.CodecContent, []byte("not a record"), true},
+		{"not a map", block.CodecContent, dump([]interface{}{"a"}), true},
+		{"missing context", block.CodecContent, dump(content(map[string]interface{}{block.ContextKey: nil})), true},
+		{"unknown version", block.CodecContent, dump(content(map[string]interface{}{block.ContextKey: uint64(9)})), true},
+		{"missing required", block.CodecContent, dump(content(map[string]interface{}{"title": nil})), true},
+		{"invalid type", block.CodecContent, dump(content(map[string]interface{}{"title": 1})), true},
+		{"parent of version 1", block.CodecContent,
+			dump(content(map[string]interface{}{"parent": link(t, block.CodecContent, "parent")})), true},
+		{"other codec", block.CodecEntity, dump(content(map[string]interface{}{block.ContextKey: uint64(1)})), true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			obj, c, err := block.DecodeWithCodec(test.rawData, test.codec,
+				block.WithMultihash(multihash.BLAKE2B_MIN+31, -1))
+			if test.err {
+				if err == nil {
+					t.Fatalf("an error is expected but %s is returned", obj)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			prefix := c.Prefix()
+			if prefix.Codec != test.codec || prefix.MhType != multihash.BLAKE2B_MIN+31 {
+				t.Fatalf("a BLAKE2b-256 CID of %#x is expected but %s is found", test.codec, c)
+			}
+		})
+	}
+}
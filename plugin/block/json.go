@@ -0,0 +1,236 @@
+package block
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/ipfs/go-cid"
+)
+
+// ParseJSON parses the JSON data into the ISCN object of 'codec'. The version
+// of schema is taken from the context, which can be a schema URL or a number,
+// or version 1 is used if the context is missing
+func ParseJSON(codec uint64, r io.Reader, opts ...EncodeOption) (IscnObject, error) {
+	data, err := decodeJSON(r)
+	if err != nil {
+		return nil, err
+	}
+
+	version, err := ParseContext(codec, data[ContextKey])
+	if err != nil {
+		return nil, err
+	}
+	delete(data, ContextKey)
+
+	return Encode(codec, version, data, opts...)
+}
+
+// UnmarshalJSON parses the JSON data generated by MarshalJSON back to the ISCN
+// object, the codec and version of schema are taken from the schema URL of
+// the context. A JSON number is parsed as a float only if it has a fraction or
+// an exponent, and floats are kept in 64 bits, so a custom float32 property is
+// parsed back as float64 of the same value
+func UnmarshalJSON(b []byte, opts ...EncodeOption) (IscnObject, error) {
+	data, err := decodeJSON(bytes.NewReader(b))
+	if err != nil {
+		return nil, err
+	}
+
+	context, ok := data[ContextKey].(string)
+	if !ok {
+		return nil, fmt.Errorf("JSON: schema URL of context is expected but '%T' is found",
+			data[ContextKey])
+	}
+
+	name, version, err := ParseSchemaURL(context)
+	if err != nil {
+		return nil, err
+	}
+	delete(data, ContextKey)
+
+	for codec, schemaName := range schemaNames {
+		if schemaName == name {
+			return Encode(codec, version, data, opts...)
+		}
+	}
+
+	return nil, fmt.Errorf("%q is not registered", name)
+}
+
+// decodeJSON decodes the JSON object and converts the values for data handlers
+func decodeJSON(r io.Reader) (map[string]interface{}, error) {
+	decoder := json.NewDecoder(r)
+	decoder.UseNumber()
+
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return nil, err
+	}
+
+	value, err := convertJSONValue(value)
+	if err != nil {
+		return nil, err
+	}
+
+	data, ok := value.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("JSON: an object is expected but '%T' is found", value)
+	}
+
+	return data, nil
+}
+
+// ParseContext returns the schema version of 'codec' specified by the context,
+// which can be a schema URL or a number, or version 1 if the context is
+// missing. The default is not the latest version, so the CID of the same input
+// is kept when a new schema version is added
+func ParseContext(codec uint64, context interface{}) (uint64, error) {
+	var version uint64
+	switch v := context.(type) {
+	case nil:
+		if _, ok := factory[codec]; !ok {
+			return 0, fmt.Errorf("%q is not registered", schemaNames[codec])
+		}
+		return 1, nil
+	case int64:
+		if v < 0 {
+			return 0, fmt.Errorf("Context: version %d is invalid", v)
+		}
+		version = uint64(v)
+	case uint64:
+		version = v
+	case string:
+		name, ver, err := ParseSchemaURL(v)
+		if err != nil {
+			return 0, err
+		}
+
+		if name != schemaNames[codec] {
+			return 0, fmt.Errorf("Context: schema %q is expected but %q is found",
+				schemaNames[codec], name)
+		}
+		version = ver
+	default:
+		return 0, fmt.Errorf("Context: schema URL is expected but '%T' is found", context)
+	}
+
+	// The versions of schema start from 1
+	if version == 0 {
+		return 0, fmt.Errorf("Context: version 0 is invalid")
+	}
+
+	return version, nil
+}
+
+// convertJSONValue converts the decoded JSON value to the value accepted by
+// the data handlers, i.e. links to Cid and numbers to int64/uint64/float64
+func convertJSONValue(value interface{}) (interface{}, error) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		if link, ok := v["/"]; ok && len(v) == 1 {
+			switch l := link.(type) {
+			case string:
+				return cid.Decode(strings.TrimPrefix(l, "/ipfs/"))
+			case map[string]interface{}:
+				if b, ok := l["bytes"].(string); ok && len(l) == 1 {
+					return base64.RawStdEncoding.DecodeString(strings.TrimRight(b, "="))
+				}
+			}
+
+			return nil, fmt.Errorf("JSON: link or bytes is expected but '%T' is found", link)
+		}
+
+		for key, elem := range v {
+			res, err := convertJSONValue(elem)
+			if err != nil {
+				return nil, err
+			}
+			v[key] = res
+		}
+		return v, nil
+	case []interface{}:
+		for i, elem := range v {
+			res, err := convertJSONValue(elem)
+			if err != nil {
+				return nil, err
+			}
+			v[i] = res
+		}
+		return v, nil
+	case json.Number:
+		return convertJSONNumber(v)
+	}
+
+	return value, nil
+}
+
+// convertJSONNumber converts the JSON number to the narrowest Go number type
+func convertJSONNumber(n json.Number) (interface{}, error) {
+	if i, err := strconv.ParseInt(n.String(), 10, 64); err == nil {
+		return i, nil
+	}
+
+	if u, err := strconv.ParseUint(n.String(), 10, 64); err == nil {
+		return u, nil
+	}
+
+	f, err := n.Float64()
+	if err != nil || math.IsInf(f, 0) {
+		return nil, fmt.Errorf("JSON: %q is not a valid number", n.String())
+	}
+	return f, nil
+}
+
+// jsonOfValue converts a plain value for JSON output, bytes are represented
+// as {"/": {"bytes": "<base64>"}} and floats always have a fraction or an
+// exponent so that they can be parsed back
+func jsonOfValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case float32:
+		// CBOR keeps floats in 64 bits, see CheckCanonical
+		return jsonOfFloat(float64(v))
+	case float64:
+		return jsonOfFloat(v)
+	case []byte:
+		return map[string]interface{}{
+			"/": map[string]string{
+				"bytes": base64.RawStdEncoding.EncodeToString(v),
+			},
+		}
+	case map[string]interface{}:
+		res := map[string]interface{}{}
+		for key, elem := range v {
+			res[key] = jsonOfValue(elem)
+		}
+		return res
+	case []interface{}:
+		res := make([]interface{}, 0, len(v))
+		for _, elem := range v {
+			res = append(res, jsonOfValue(elem))
+		}
+		return res
+	}
+
+	return value
+}
+
+// jsonOfFloat converts a float for JSON output, e.g. 1.0 is written as "1.0"
+// instead of "1" so that it is not parsed back as an integer
+func jsonOfFloat(f float64) interface{} {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		// Not representable in JSON, left to the JSON encoder to fail
+		return f
+	}
+
+	s := strconv.FormatFloat(f, 'g', -1, 64)
+	if !strings.ContainsAny(s, ".e") {
+		s += ".0"
+	}
+	return json.Number(s)
+}
@@ -0,0 +1,194 @@
+package block_test
+
+import (
+	"bytes"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/likecoin/iscn-ipld/plugin/block"
+
+	cbor "github.com/ipfs/go-ipld-cbor"
+)
+
+func TestParseContext(t *testing.T) {
+	tests := []struct {
+		name    string
+		codec   uint64
+		context interface{}
+		version uint64
+		err     bool
+	}{
+		{"missing", block.CodecEntity, nil, 1, false},
+		{"missing with versions", block.CodecContent, nil, 1, false},
+		{"number", block.CodecContent, int64(2), 2, false},
+		{"unsigned number", block.CodecContent, uint64(3), 3, false},
+		{"schema URL", block.CodecContent, "schema/content-v2", 2, false},
+		{"number 0", block.CodecEntity, int64(0), 0, true},
+		{"unsigned number 0", block.CodecEntity, uint64(0), 0, true},
+		{"schema URL v0", block.CodecEntity, "schema/entity-v0", 0, true},
+		{"negative number", block.CodecEntity, int64(-1), 0, true},
+		{"other schema", block.CodecEntity, "schema/content-v1", 0, true},
+		{"invalid URL", block.CodecEntity, "entity", 0, true},
+		{"invalid type", block.CodecEntity, 1.5, 0, true},
+		{"not registered", 0x0300 - 1, nil, 0, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			version, err := block.ParseContext(test.codec, test.context)
+			if test.err {
+				if err == nil {
+					t.Fatalf("an error is expected but version %d is returned", version)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if version != test.version {
+				t.Fatalf("version %d is expected but %d is returned", test.version, version)
+			}
+		})
+	}
+}
+
+func TestParseJSON(t *testing.T) {
+	tests := []struct {
+		name string
+		json string
+		err  bool
+	}{
+		{"missing context", `{"id": "llc://abc", "name": "Alice"}`, false},
+		{"number", `{"context": 1, "id": "llc://abc"}`, false},
+		{"schema URL", `{"context": "schema/entity-v1", "id": "llc://abc"}`, false},
+		{"number 0", `{"context": 0, "id": "llc://abc"}`, true},
+		{"schema URL v0", `{"context": "schema/entity-v0", "id": "llc://abc"}`, true},
+		{"negative number", `{"context": -1, "id": "llc://abc"}`, true},
+		{"other schema", `{"context": "schema/content-v1", "id": "llc://abc"}`, true},
+		{"invalid context", `{"context": 1.5, "id": "llc://abc"}`, true},
+		{"not implemented", `{"context": 2, "id": "llc://abc"}`, true},
+		{"missing id", `{"name": "Alice"}`, true},
+		{"not an object", `["llc://abc"]`, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			obj, err := block.ParseJSON(block.CodecEntity, strings.NewReader(test.json))
+			if test.err {
+				if err == nil {
+					t.Fatalf("an error is expected but %s is returned", obj)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
+
+func TestVersionZero(t *testing.T) {
+	data := map[string]interface{}{
+		"id": "llc://abc",
+	}
+
+	if _, err := block.Encode(block.CodecEntity, 0, data); err == nil {
+		t.Fatal("Encode: an error is expected for version 0")
+	}
+
+	rawData, err := cbor.DumpObject(map[string]interface{}{
+		block.ContextKey: uint64(0),
+		"id":             "llc://abc",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, _, err := block.DecodeWithCodec(rawData, block.CodecEntity); err == nil {
+		t.Fatal("DecodeWithCodec: an error is expected for version 0")
+	}
+
+	c := link(t, block.CodecEntity, string(rawData))
+	if _, err := block.Decode(rawData, c); err == nil {
+		t.Fatal("Decode: an error is expected for version 0")
+	}
+}
+
+func TestUnmarshalJSONRoundTrip(t *testing.T) {
+	tests := []struct {
+		name   string
+		custom interface{}
+	}{
+		{"string", "value"},
+		{"integer", int64(-1)},
+		{"unsigned integer", uint64(math.MaxUint64)},
+		{"float", 1.5},
+		{"integral float", 1.0},
+		{"negative zero", math.Copysign(0, -1)},
+		{"large float", 1e21},
+		{"small float", 1e-7},
+		{"float32", float32(0.1)},
+		{"bytes", []byte{0, 1, 2}},
+		{"link", link(t, block.CodecEntity, "bob")},
+		{"nested", map[string]interface{}{
+			"array": []interface{}{1.0, int64(1), "1", []byte{1}},
+		}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			obj := encode(t, block.CodecEntity, 1, map[string]interface{}{
+				"id":     "llc://alice",
+				"custom": test.custom,
+			})
+
+			b, err := obj.MarshalJSON()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			res, err := block.UnmarshalJSON(b)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !bytes.Equal(res.RawData(), obj.RawData()) {
+				t.Fatalf("%x is expected but %x is found from %s", obj.RawData(), res.RawData(), b)
+			}
+		})
+	}
+}
+
+func TestUnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		name string
+		json string
+		err  bool
+	}{
+		{"schema URL", `{"context": "schema/entity-v1", "id": "llc://abc"}`, false},
+		{"number", `{"context": 1, "id": "llc://abc"}`, true},
+		{"missing context", `{"id": "llc://abc"}`, true},
+		{"unknown schema", `{"context": "schema/unknown-v1", "id": "llc://abc"}`, true},
+		{"invalid bytes", `{"context": "schema/entity-v1", "id": "llc://abc", "b": {"/": {"bytes": 1}}}`, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			obj, err := block.UnmarshalJSON([]byte(test.json))
+			if test.err {
+				if err == nil {
+					t.Fatalf("an error is expected but %s is returned", obj)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
@@ -0,0 +1,215 @@
+package block
+
+import (
+	"fmt"
+	"net/url"
+
+	"github.com/ipfs/go-cid"
+	"gitlab.com/c0b/go-ordered-json"
+)
+
+// ==================================================
+// JSON-LD
+// ==================================================
+
+// DefaultJSONLDVocabulary is the default IRI of the vocabulary of ISCN for the
+// terms which are not mapped to schema.org. It is provisional as there is no
+// published vocabulary of ISCN yet, see WithJSONLDVocabulary
+const DefaultJSONLDVocabulary = "http://iscn.io/"
+
+// JSONLDOption is an option for MarshalJSONLD
+type JSONLDOption func(*jsonLDOptions)
+
+// jsonLDOptions is the options of MarshalJSONLD
+type jsonLDOptions struct {
+	vocabulary string
+}
+
+// WithJSONLDVocabulary sets the IRI of the vocabulary of ISCN, which should be
+// an absolute IRI
+func WithJSONLDVocabulary(iri string) JSONLDOption {
+	return func(o *jsonLDOptions) {
+		o.vocabulary = iri
+	}
+}
+
+// jsonLDTypes maps the schema names to the types of JSON-LD nodes
+var jsonLDTypes = map[string]string{
+	"iscn":         "ISCN",
+	"rights":       "Rights",
+	"right":        "Right",
+	"stakeholders": "Stakeholders",
+	"stakeholder":  "Stakeholder",
+	"content":      "Content",
+	"entity":       "Entity",
+	"timeperiod":   "TimePeriod",
+}
+
+// jsonLDContext creates the @context of JSON-LD with the 'vocabulary' of ISCN
+func jsonLDContext(vocabulary string) *ordered.OrderedMap {
+	dateTime := func(id string) *ordered.OrderedMap {
+		om := ordered.NewOrderedMap()
+		om.Set("@id", id)
+		om.Set("@type", "schema:DateTime")
+		return om
+	}
+
+	om := ordered.NewOrderedMap()
+	om.Set("@vocab", vocabulary)
+	om.Set("schema", "http://schema.org/")
+	om.Set("schemaVersion", "schema:schemaVersion")
+	om.Set("timestamp", dateTime("schema:dateCreated"))
+	om.Set("version", "schema:version")
+	om.Set("name", "schema:name")
+	om.Set("description", "schema:description")
+	om.Set("title", "schema:headline")
+	om.Set("edition", "schema:bookEdition")
+	om.Set("tags", "schema:keywords")
+	om.Set("from", dateTime("schema:startDate"))
+	om.Set("to", dateTime("schema:endDate"))
+	om.Set("territory", "schema:spatialCoverage")
+	return om
+}
+
+// jsonLDLink converts a link to a JSON-LD node reference
+func jsonLDLink(c cid.Cid) *ordered.OrderedMap {
+	om := ordered.NewOrderedMap()
+	om.Set("@id", "ipfs://"+c.String())
+	return om
+}
+
+// MarshalJSONLD converts the block to JSON-LD format. Nested objects are
+// embedded as typed nodes and links are converted to node references
+func (b *Base) MarshalJSONLD(opts ...JSONLDOption) ([]byte, error) {
+	o := &jsonLDOptions{
+		vocabulary: DefaultJSONLDVocabulary,
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	u, err := url.Parse(o.vocabulary)
+	if err != nil || !u.IsAbs() {
+		return nil, fmt.Errorf("JSON-LD: %q is not an absolute IRI", o.vocabulary)
+	}
+
+	om := ordered.NewOrderedMap()
+	om.Set("@context", jsonLDContext(o.vocabulary))
+
+	if b.cid != nil {
+		om.Set("@id", "ipfs://"+b.cid.String())
+	}
+
+	if err := b.toJSONLD(om); err != nil {
+		return nil, err
+	}
+
+	return om.MarshalJSON()
+}
+
+// toJSONLD prepares the node of the block for MarshalJSONLD
+func (b *Base) toJSONLD(om *ordered.OrderedMap) error {
+	ty, ok := jsonLDTypes[b.name]
+	if !ok {
+		ty = b.name
+	}
+	om.Set("@type", ty)
+
+	if !b.isNested {
+		om.Set("schemaVersion", b.version)
+	}
+
+	for _, key := range b.keys {
+		if key == ContextKey {
+			continue
+		}
+
+		if _, exist := b.obj[key]; !exist {
+			continue
+		}
+
+		value, err := jsonLDOfHandler(b.data[key])
+		if err != nil {
+			return err
+		}
+		om.Set(key, value)
+	}
+
+	for _, key := range sortedKeys(b.custom) {
+		om.Set(key, jsonLDOfValue(b.custom[key]))
+	}
+
+	return nil
+}
+
+// jsonLDNode is the nested object which can be converted to a JSON-LD node
+type jsonLDNode interface {
+	toJSONLD(*ordered.OrderedMap) error
+}
+
+// jsonLDOfHandler converts the value of a data handler for JSON-LD
+func jsonLDOfHandler(handler Data) (interface{}, error) {
+	switch d := handler.(type) {
+	case *Object:
+		n, ok := d.object.(jsonLDNode)
+		if !ok {
+			return nil, fmt.Errorf("Object: %q cannot be converted to JSON-LD", d.GetKey())
+		}
+
+		om := ordered.NewOrderedMap()
+		if err := n.toJSONLD(om); err != nil {
+			return nil, err
+		}
+		return om, nil
+
+	case *DataArray:
+		res := make([]interface{}, 0, len(d.array))
+		for _, elem := range d.array {
+			value, err := jsonLDOfHandler(elem)
+			if err != nil {
+				return nil, err
+			}
+			res = append(res, value)
+		}
+		return res, nil
+	}
+
+	// Links are found from the IPLD data model, while the other values use the
+	// JSON form, e.g. the ISCN kernel ID
+	m := map[string]interface{}{}
+	if err := handler.ToDataModel(&m); err != nil {
+		return nil, err
+	}
+
+	if c, ok := m[handler.GetKey()].(cid.Cid); ok {
+		return jsonLDLink(c), nil
+	}
+
+	om := ordered.NewOrderedMap()
+	if err := handler.ToJSON(om); err != nil {
+		return nil, err
+	}
+	return om.Get(handler.GetKey()), nil
+}
+
+// jsonLDOfValue converts a custom value for JSON-LD
+func jsonLDOfValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case cid.Cid:
+		return jsonLDLink(v)
+	case map[string]interface{}:
+		om := ordered.NewOrderedMap()
+		for _, key := range sortedKeys(v) {
+			om.Set(key, jsonLDOfValue(v[key]))
+		}
+		return om
+	case []interface{}:
+		res := make([]interface{}, 0, len(v))
+		for _, elem := range v {
+			res = append(res, jsonLDOfValue(elem))
+		}
+		return res
+	}
+
+	return jsonOfValue(value)
+}
@@ -0,0 +1,87 @@
+package block_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/likecoin/iscn-ipld/plugin/block"
+)
+
+func TestMarshalJSONLD(t *testing.T) {
+	holder := encode(t, block.CodecEntity, 1, map[string]interface{}{
+		"id":   "llc://holder",
+		"name": "Alice",
+	})
+	obj := encode(t, block.CodecRights, 2, map[string]interface{}{
+		"rights": []interface{}{
+			map[string]interface{}{
+				"holder": holder.Cid(),
+				"type":   "License",
+				"terms":  holder.Cid(),
+				"period": map[string]interface{}{"from": "2020-01-01T00:00:00Z"},
+			},
+		},
+	})
+
+	tests := []struct {
+		name  string
+		vocab string
+		err   bool
+	}{
+		{"default", "", false},
+		{"custom", "https://example.com/iscn#", false},
+		{"relative", "iscn/", true},
+		{"invalid", "http://[::1", true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			expected := block.DefaultJSONLDVocabulary
+			opts := []block.JSONLDOption{}
+			if test.vocab != "" {
+				expected = test.vocab
+				opts = append(opts, block.WithJSONLDVocabulary(test.vocab))
+			}
+
+			b, err := obj.MarshalJSONLD(opts...)
+			if test.err {
+				if err == nil {
+					t.Fatalf("an error is expected for %q", test.vocab)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var doc struct {
+				Context map[string]interface{} `json:"@context"`
+				ID      string                 `json:"@id"`
+				Type    string                 `json:"@type"`
+				Rights  []struct {
+					Type   string `json:"@type"`
+					Holder struct {
+						ID string `json:"@id"`
+					} `json:"holder"`
+				} `json:"rights"`
+			}
+			if err := json.Unmarshal(b, &doc); err != nil {
+				t.Fatal(err)
+			}
+
+			if doc.Context["@vocab"] != expected {
+				t.Fatalf("vocabulary %q is expected but %v is found", expected, doc.Context["@vocab"])
+			}
+
+			if doc.ID != "ipfs://"+obj.Cid().String() || doc.Type != "Rights" {
+				t.Fatalf("the node of %s is not matched: %s", obj.Cid(), b)
+			}
+
+			if len(doc.Rights) != 1 || doc.Rights[0].Type != "Right" ||
+				doc.Rights[0].Holder.ID != "ipfs://"+holder.Cid().String() {
+				t.Fatalf("the nested node is not matched: %s", b)
+			}
+		})
+	}
+}
@@ -0,0 +1,195 @@
+package block
+
+import (
+	"fmt"
+
+	"gitlab.com/c0b/go-ordered-json"
+)
+
+// ==================================================
+// JSON Schema
+// ==================================================
+
+// JSONSchemaDraft is the version of JSON Schema generated
+const JSONSchemaDraft = "http://json-schema.org/draft-07/schema#"
+
+// JSONSchemaData is the optional interface of a data handler to describe the
+// JSON values accepted by Set in JSON Schema. The value of a data handler
+// without this interface is not restricted
+type JSONSchemaData interface {
+	JSONSchema() *ordered.OrderedMap
+}
+
+// LinkJSONSchema returns the JSON Schema of a link, i.e. {"/": "<cid>"}
+func LinkJSONSchema() *ordered.OrderedMap {
+	cid := ordered.NewOrderedMap()
+	cid.Set("type", "string")
+	cid.Set("format", "cid")
+
+	properties := ordered.NewOrderedMap()
+	properties.Set("/", cid)
+
+	om := ordered.NewOrderedMap()
+	om.Set("type", "object")
+	om.Set("properties", properties)
+	om.Set("required", []string{"/"})
+	om.Set("additionalProperties", false)
+	return om
+}
+
+// BytesJSONSchema returns the JSON Schema of bytes, i.e.
+// {"/": {"bytes": "<base64>"}}
+func BytesJSONSchema() *ordered.OrderedMap {
+	bytes := ordered.NewOrderedMap()
+	bytes.Set("type", "string")
+	bytes.Set("contentEncoding", "base64")
+
+	inner := ordered.NewOrderedMap()
+	inner.Set("type", "object")
+	inner.Set("properties", map[string]interface{}{"bytes": bytes})
+	inner.Set("required", []string{"bytes"})
+	inner.Set("additionalProperties", false)
+
+	om := ordered.NewOrderedMap()
+	om.Set("type", "object")
+	om.Set("properties", map[string]interface{}{"/": inner})
+	om.Set("required", []string{"/"})
+	om.Set("additionalProperties", false)
+	return om
+}
+
+// jsonSchemaOfHandler returns the JSON Schema of a data handler
+func jsonSchemaOfHandler(handler Data) *ordered.OrderedMap {
+	if d, ok := handler.(JSONSchemaData); ok {
+		return d.JSONSchema()
+	}
+	return ordered.NewOrderedMap()
+}
+
+// jsonSchemaNode is the ISCN object which can be described in JSON Schema
+type jsonSchemaNode interface {
+	jsonSchema() *ordered.OrderedMap
+}
+
+// jsonSchema describes the properties of the block without context, the
+// optional properties may be null as SetData skips them
+func (b *Base) jsonSchema() *ordered.OrderedMap {
+	properties := ordered.NewOrderedMap()
+	required := []string{}
+	for _, key := range b.keys {
+		if key == ContextKey {
+			continue
+		}
+
+		handler := b.data[key]
+		schema := jsonSchemaOfHandler(handler)
+		if handler.IsRequired() {
+			required = append(required, key)
+		} else {
+			null := ordered.NewOrderedMap()
+			null.Set("type", "null")
+
+			optional := ordered.NewOrderedMap()
+			optional.Set("anyOf", []interface{}{schema, null})
+			schema = optional
+		}
+		properties.Set(key, schema)
+	}
+
+	om := ordered.NewOrderedMap()
+	om.Set("type", "object")
+	om.Set("properties", properties)
+	om.Set("required", required)
+
+	// Custom properties are kept as is
+	om.Set("additionalProperties", true)
+	return om
+}
+
+// contextJSONSchema describes the context of schema 'version' of 'codec',
+// which is either the schema URL or the version number
+func contextJSONSchema(codec uint64, version uint64) *ordered.OrderedMap {
+	url := ordered.NewOrderedMap()
+	url.Set("const", schemaURL(schemaNames[codec], version))
+
+	number := ordered.NewOrderedMap()
+	number.Set("const", version)
+
+	om := ordered.NewOrderedMap()
+	om.Set("anyOf", []interface{}{url, number})
+	return om
+}
+
+// JSONSchema generates the JSON Schema document of the registered schema
+// 'version' of 'codec', which describes the JSON accepted by ParseJSON. The
+// context is required unless 'version' is 1, the default of ParseJSON
+func JSONSchema(codec uint64, version uint64) ([]byte, error) {
+	schemas, ok := factory[codec]
+	if !ok {
+		return nil, fmt.Errorf("%q is not registered", schemaNames[codec])
+	}
+
+	if version == 0 || version > (uint64)(len(schemas)) {
+		return nil, fmt.Errorf("<%s (v%d)> is not implemented", schemaNames[codec], version)
+	}
+
+	obj, err := schemas[version-1]()
+	if err != nil {
+		return nil, err
+	}
+
+	n, ok := obj.(jsonSchemaNode)
+	if !ok {
+		return nil, fmt.Errorf("<%s (v%d)> cannot be described in JSON Schema",
+			schemaNames[codec], version)
+	}
+	schema := n.jsonSchema()
+
+	properties := ordered.NewOrderedMap()
+	properties.Set(ContextKey, contextJSONSchema(codec, version))
+	iter := schema.Get("properties").(*ordered.OrderedMap).EntriesIter()
+	for {
+		pair, ok := iter()
+		if !ok {
+			break
+		}
+		properties.Set(pair.Key, pair.Value)
+	}
+
+	required := schema.Get("required").([]string)
+	if version != 1 {
+		required = append([]string{ContextKey}, required...)
+	}
+
+	om := ordered.NewOrderedMap()
+	om.Set("$schema", JSONSchemaDraft)
+	om.Set("title", fmt.Sprintf("%s v%d", schemaNames[codec], version))
+	om.Set("type", "object")
+	om.Set("properties", properties)
+	om.Set("required", required)
+	om.Set("additionalProperties", true)
+	return om.MarshalJSON()
+}
+
+// JSONSchemas generates the JSON Schema documents of every registered schema
+// version of ISCN objects, indexed by the schema URLs of context
+func JSONSchemas() (map[string][]byte, error) {
+	res := map[string][]byte{}
+	for codec, schemas := range factory {
+		if !IsIscnObject(codec) {
+			continue
+		}
+
+		for i := range schemas {
+			version := uint64(i + 1)
+			doc, err := JSONSchema(codec, version)
+			if err != nil {
+				return nil, err
+			}
+
+			res[schemaURL(schemaNames[codec], version)] = doc
+		}
+	}
+
+	return res, nil
+}
@@ -0,0 +1,133 @@
+package block_test
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/likecoin/iscn-ipld/plugin/block"
+)
+
+// lookup returns the JSON value of the schema document under 'path'
+func lookup(t *testing.T, doc []byte, path string) string {
+	t.Helper()
+
+	var value interface{}
+	if err := json.Unmarshal(doc, &value); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, key := range strings.Split(path, "/") {
+		switch v := value.(type) {
+		case map[string]interface{}:
+			value = v[key]
+		case []interface{}:
+			index, err := strconv.Atoi(key)
+			if err != nil || index >= len(v) {
+				t.Fatalf("%q is not found", path)
+			}
+			value = v[index]
+		default:
+			t.Fatalf("%q is not found", path)
+		}
+	}
+
+	res, err := json.Marshal(value)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(res)
+}
+
+func TestJSONSchema(t *testing.T) {
+	link := `{"additionalProperties":false,"description":"Link to entity",` +
+		`"properties":{"/":{"format":"cid","type":"string"}},"required":["/"],"type":"object"}`
+
+	tests := []struct {
+		name     string
+		codec    uint64
+		version  uint64
+		path     string
+		expected string
+	}{
+		{"required of v1", block.CodecContent, 1, "required",
+			`["type","version","fingerprint","title"]`},
+		{"required of v3", block.CodecContent, 3, "required",
+			`["context","type","version","fingerprint","title"]`},
+		{"context", block.CodecContent, 2, "properties/context",
+			`{"anyOf":[{"const":"schema/content-v2"},{"const":2}]}`},
+		{"filtered string", block.CodecStakeholders, 3, "properties/stakeholders/items/properties/type/enum",
+			`["Contributor","Creator","Editor","Escrow","FootprintStakeholder","Publisher"]`},
+		{"integer range", block.CodecStakeholders, 3, "properties/stakeholders/items/properties/sharing",
+			`{"maximum":4294967295,"minimum":0,"type":"integer"}`},
+		{"link", block.CodecStakeholders, 3, "properties/stakeholders/items/properties/stakeholder", link},
+		{"nested object", block.CodecRights, 1, "properties/rights/items/properties/period/anyOf/0/type",
+			`"object"`},
+		{"nested timestamp", block.CodecRights, 1,
+			"properties/rights/items/properties/period/anyOf/0/properties/from/anyOf/0/type", `"string"`},
+		{"timestamp", block.CodecISCN, 1, "properties/timestamp/pattern",
+			`"` + strings.ReplaceAll(block.TimestampPattern, `\`, `\\`) + `"`},
+		{"custom data handler", block.CodecISCN, 3, "properties/id/anyOf/0/pattern",
+			`"^1/[1-9A-HJ-NP-Za-km-z]{32,44}$"`},
+		{"optional", block.CodecISCN, 3, "properties/parent/anyOf/1", `{"type":"null"}`},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			doc, err := block.JSONSchema(test.codec, test.version)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if res := lookup(t, doc, test.path); res != test.expected {
+				t.Fatalf("%s is expected but %s is found", test.expected, res)
+			}
+		})
+	}
+}
+
+func TestJSONSchemaNotImplemented(t *testing.T) {
+	tests := []struct {
+		name    string
+		codec   uint64
+		version uint64
+	}{
+		{"version 0", block.CodecContent, 0},
+		{"unknown version", block.CodecContent, 9},
+		{"unregistered codec", 0x71, 1},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if _, err := block.JSONSchema(test.codec, test.version); err == nil {
+				t.Fatal("an error is expected")
+			}
+		})
+	}
+}
+
+func TestJSONSchemas(t *testing.T) {
+	docs, err := block.JSONSchemas()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, url := range []string{
+		"schema/iscn-v1",
+		"schema/iscn-v3",
+		"schema/rights-v2",
+		"schema/stakeholders-v3",
+		"schema/content-v3",
+		"schema/entity-v1",
+	} {
+		if _, ok := docs[url]; !ok {
+			t.Fatalf("%q is not found", url)
+		}
+	}
+
+	// Nested objects are not ISCN objects
+	if _, ok := docs["schema/right-v1"]; ok {
+		t.Fatal(`"schema/right-v1" is not expected`)
+	}
+}
@@ -2,6 +2,7 @@ package kernel
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/btcsuite/btcutil/base58"
 	"github.com/likecoin/iscn-ipld/plugin/block"
@@ -35,13 +36,32 @@ func (d *ID) Prototype() block.Data {
 	}
 }
 
+// Copy creates a deep copy of ID
+func (d *ID) Copy() block.Data {
+	id := make([]byte, len(d.id))
+	copy(id, d.id)
+
+	return &ID{
+		DataBase: d.DataBase.Copy(),
+		id:       id,
+	}
+}
+
 // GetID returns the human readable ID
 func (d *ID) GetID() string {
 	return fmt.Sprintf("1/%s", base58.Encode(d.id))
 }
 
-// Set the value of ID
+// Set the value of ID, the human readable ID is also accepted
 func (d *ID) Set(data interface{}) error {
+	if s, ok := data.(string); ok {
+		if !strings.HasPrefix(s, "1/") {
+			return fmt.Errorf("ID: %q is not a valid ID", s)
+		}
+
+		data = base58.Decode(s[2:])
+	}
+
 	if id, ok := data.([]byte); ok {
 		if len(id) != 32 {
 			return fmt.Errorf("ID: should length 32 but %d is found", len(id))
@@ -76,6 +96,12 @@ func (d *ID) ToJSON(om *ordered.OrderedMap) error {
 	return nil
 }
 
+// ToDataModel prepares the data for IPLD data model
+func (d *ID) ToDataModel(m *map[string]interface{}) error {
+	(*m)[d.GetKey()] = d.id
+	return nil
+}
+
 // Resolve resolves the value
 func (d *ID) Resolve(path []string) (interface{}, []string, error) {
 	if len(path) != 0 {
@@ -84,3 +110,14 @@ func (d *ID) Resolve(path []string) (interface{}, []string, error) {
 
 	return d.GetID(), nil, nil
 }
+
+// JSONSchema describes the ISCN ID as "1/<base58>" or 32 bytes
+func (d *ID) JSONSchema() *ordered.OrderedMap {
+	id := ordered.NewOrderedMap()
+	id.Set("type", "string")
+	id.Set("pattern", "^1/[1-9A-HJ-NP-Za-km-z]{32,44}$")
+
+	om := ordered.NewOrderedMap()
+	om.Set("anyOf", []interface{}{id, block.BytesJSONSchema()})
+	return om
+}
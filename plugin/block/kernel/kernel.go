@@ -13,102 +13,106 @@ const (
 
 // Register registers the schema of ISCN kernel block
 func Register() {
-	block.RegisterIscnObjectFactory(
+	block.RegisterSchemaSpecs(
 		block.CodecISCN,
 		SchemaName,
-		[]block.CodecFactoryFunc{
-			newSchemaV1,
+		[]block.SchemaSpec{
+			schemaV1,
+			schemaV2,
+			schemaV3,
 		},
 	)
+
+	block.RegisterMigration(block.CodecISCN, 1, block.MigrateAsIs)
+	block.RegisterMigration(block.CodecISCN, 2, block.MigrateAsIs)
 }
 
 // ==================================================
-// base
+// schemaV1
 // ==================================================
 
-// base is the base struct for ISCN kernel (codec 0x0264)
-type base struct {
-	*block.Base
-
-	id *ID
-}
-
-func newBase(version uint64, schema []block.Data, id *ID) (*base, error) {
-	blockBase, err := block.NewBase(
-		block.CodecISCN,
-		SchemaName,
-		version,
-		schema,
-	)
-	if err != nil {
-		return nil, err
-	}
-
-	return &base{
-		Base: blockBase,
-		id:   id,
-	}, nil
+// schemaV1 is the specification of ISCN kernel V1
+var schemaV1 = block.SchemaSpec{
+	Fields: []block.FieldSpec{
+		{Key: "id", Type: block.FieldCustom, Required: true, Handler: newID},
+		{Key: "timestamp", Type: block.FieldTimestamp, Required: true},
+		{Key: "version", Type: block.FieldNumber, Required: true, Number: block.Uint64T},
+		{Key: "parent", Type: block.FieldLink, Codec: block.CodecISCN},
+		{Key: "rights", Type: block.FieldLink, Required: true, Codec: block.CodecRights},
+		{Key: "stakeholders", Type: block.FieldLink, Required: true, Codec: block.CodecStakeholders},
+		{Key: "content", Type: block.FieldLink, Required: true, Codec: block.CodecContent},
+	},
+	Validate: validate,
+	String:   toString,
+	Loggable: loggable,
 }
 
-// github.com/ipfs/go-block-format.Block interface
+// ==================================================
+// schemaV2
+// ==================================================
 
-// Loggable returns a map the type of IPLD Link
-func (b *base) Loggable() map[string]interface{} {
-	l := b.Base.Loggable()
-	l["id"] = b.id.GetID()
-	return l
+// schemaV2 is the specification of ISCN kernel V2, the links are encoded as
+// CBOR tag 42
+var schemaV2 = block.SchemaSpec{
+	Fields: []block.FieldSpec{
+		{Key: "id", Type: block.FieldCustom, Required: true, Handler: newID},
+		{Key: "timestamp", Type: block.FieldTimestamp, Required: true},
+		{Key: "version", Type: block.FieldNumber, Required: true, Number: block.Uint64T},
+		{Key: "parent", Type: block.FieldLink, Codec: block.CodecISCN, Tagged: true},
+		{Key: "rights", Type: block.FieldLink, Required: true, Codec: block.CodecRights, Tagged: true},
+		{Key: "stakeholders", Type: block.FieldLink, Required: true, Codec: block.CodecStakeholders, Tagged: true},
+		{Key: "content", Type: block.FieldLink, Required: true, Codec: block.CodecContent, Tagged: true},
+	},
+	Validate: validate,
+	String:   toString,
+	Loggable: loggable,
 }
 
-// String is a helper for output
-func (b *base) String() string {
-	return fmt.Sprintf("<%s (v%d): %s>", b.GetName(), b.GetVersion(), b.id.GetID())
+// ==================================================
+// schemaV3
+// ==================================================
+
+// schemaV3 is the specification of ISCN kernel V3, the links are encoded as
+// CBOR tag 42 and the numbers are encoded as CBOR integers
+var schemaV3 = block.SchemaSpec{
+	Fields: []block.FieldSpec{
+		{Key: "id", Type: block.FieldCustom, Required: true, Handler: newID},
+		{Key: "timestamp", Type: block.FieldTimestamp, Required: true},
+		{Key: "version", Type: block.FieldNumber, Required: true, Number: block.Uint64T, Native: true},
+		{Key: "parent", Type: block.FieldLink, Codec: block.CodecISCN, Tagged: true},
+		{Key: "rights", Type: block.FieldLink, Required: true, Codec: block.CodecRights, Tagged: true},
+		{Key: "stakeholders", Type: block.FieldLink, Required: true, Codec: block.CodecStakeholders, Tagged: true},
+		{Key: "content", Type: block.FieldLink, Required: true, Codec: block.CodecContent, Tagged: true},
+	},
+	Validate: validate,
+	String:   toString,
+	Loggable: loggable,
 }
 
 // ==================================================
-// schemaV1
+// Hooks
 // ==================================================
 
-// schemaV1 represents an ISCN kernel V1
-type schemaV1 struct {
-	*base
+// newID creates the data handler of the ISCN ID
+func newID() block.Data {
+	return NewID()
+}
 
-	version *block.Number
-	parent  *block.Cid
+// validate the parent against the version
+func validate(b *block.Base) error {
+	return block.ValidateParent(
+		b.GetHandler("version").(*block.Number),
+		b.GetHandler("parent").(*block.Cid),
+	)
 }
 
-var _ block.IscnObject = (*schemaV1)(nil)
-
-func newSchemaV1() (block.Codec, error) {
-	id := NewID()
-	version := block.NewNumber("version", true, block.Uint64T)
-	parent := block.NewCid("parent", false, block.CodecISCN)
-
-	schema := []block.Data{
-		id,
-		block.NewTimestamp("timestamp", true),
-		version,
-		parent,
-		block.NewCid("rights", true, block.CodecRights),
-		block.NewCid("stakeholders", true, block.CodecStakeholders),
-		block.NewCid("content", true, block.CodecContent),
-	}
-
-	iscnKernelBase, err := newBase(1, schema, id)
-	if err != nil {
-		return nil, err
-	}
-
-	obj := schemaV1{
-		base:    iscnKernelBase,
-		version: version,
-		parent:  parent,
-	}
-	iscnKernelBase.SetValidator(obj.Validate)
-
-	return &obj, nil
+// toString shows the ISCN ID in the output
+func toString(b *block.Base) string {
+	return fmt.Sprintf("<%s (v%d): %s>", b.GetName(), b.GetVersion(),
+		b.GetHandler("id").(*ID).GetID())
 }
 
-// Validate the data
-func (o *schemaV1) Validate() error {
-	return block.ValidateParent(o.version, o.parent)
+// loggable adds the ISCN ID to the loggable map
+func loggable(b *block.Base, l map[string]interface{}) {
+	l["id"] = b.GetHandler("id").(*ID).GetID()
 }
@@ -0,0 +1,87 @@
+package kernel_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/btcsuite/btcutil/base58"
+	"github.com/likecoin/iscn-ipld/plugin/block"
+	"github.com/likecoin/iscn-ipld/plugin/block/kernel"
+	"github.com/likecoin/iscn-ipld/plugin/internal/blocktest"
+)
+
+func TestKernelSpec(t *testing.T) {
+	kernel.Register()
+
+	id := make([]byte, 32)
+	id[0] = 1
+	humanID := fmt.Sprintf("1/%s", base58.Encode(id))
+
+	data := func(version uint64, parent bool) map[string]interface{} {
+		m := map[string]interface{}{
+			"id":           id,
+			"timestamp":    "2020-01-01T00:00:00Z",
+			"version":      version,
+			"rights":       blocktest.Link(t, block.CodecRights, "rights"),
+			"stakeholders": blocktest.Link(t, block.CodecStakeholders, "stakeholders"),
+			"content":      blocktest.Link(t, block.CodecContent, "content"),
+		}
+		if parent {
+			m["parent"] = blocktest.Link(t, block.CodecISCN, "parent")
+		}
+		return m
+	}
+
+	tests := []struct {
+		name    string
+		version uint64
+		data    map[string]interface{}
+		err     bool
+	}{
+		{"v1", 1, data(1, false), false},
+		{"v2", 2, data(1, false), false},
+		{"v3", 3, data(1, false), false},
+		{"v3 with parent", 3, data(2, true), false},
+		{"parent of version 1", 2, data(1, true), true},
+		{"no parent", 3, data(2, false), true},
+		{"invalid ID", 3, map[string]interface{}{"id": "2/abc"}, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			obj, err := block.Encode(block.CodecISCN, test.version, test.data)
+			if test.err {
+				if err == nil {
+					t.Fatalf("an error is expected but %s is returned", obj)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			expected := fmt.Sprintf("<iscn (v%d): %s>", test.version, humanID)
+			if s := fmt.Sprint(obj); s != expected {
+				t.Fatalf("%q is expected but %q is found", expected, s)
+			}
+
+			if l := obj.Loggable(); l["id"] != humanID || l["version"] != test.version {
+				t.Fatalf("the ID and version are expected in %v", l)
+			}
+
+			copied := obj.Copy().(block.IscnObject)
+			if s := fmt.Sprint(copied); s != expected {
+				t.Fatalf("%q is expected for the copy but %q is found", expected, s)
+			}
+
+			value, _, err := obj.Resolve([]string{"id"})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if value != humanID {
+				t.Fatalf("%q is expected but %v is found", humanID, value)
+			}
+		})
+	}
+}
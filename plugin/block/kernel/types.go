@@ -0,0 +1,69 @@
+package kernel
+
+import (
+	"github.com/ipfs/go-cid"
+	"github.com/likecoin/iscn-ipld/plugin/block"
+)
+
+// ==================================================
+// Kernel
+// ==================================================
+
+// Kernel is the typed struct of an ISCN kernel, SchemaVersion is the schema
+// version to encode with. ID is the 32 bytes of the ISCN ID
+type Kernel struct {
+	SchemaVersion uint64
+
+	ID           []byte
+	Timestamp    string
+	Version      uint64
+	Parent       cid.Cid
+	Rights       cid.Cid
+	Stakeholders cid.Cid
+	Content      cid.Cid
+
+	Custom map[string]interface{}
+}
+
+// FromObject reads the ISCN kernel from an ISCN object
+func FromObject(obj block.IscnObject) (*Kernel, error) {
+	f, err := block.ReadFields(obj, block.CodecISCN)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &Kernel{
+		SchemaVersion: obj.GetVersion(),
+		ID:            f.Bytes("id"),
+		Timestamp:     f.String("timestamp"),
+		Version:       f.Uint64("version"),
+		Parent:        f.Cid("parent"),
+		Rights:        f.Cid("rights"),
+		Stakeholders:  f.Cid("stakeholders"),
+		Content:       f.Cid("content"),
+	}
+	res.Custom = f.Custom()
+
+	if err := f.Err(); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// ToDataModel converts the ISCN kernel to IPLD data model without context
+func (k *Kernel) ToDataModel() map[string]interface{} {
+	data := block.NewDataModel(k.Custom)
+	data["id"] = k.ID
+	data["timestamp"] = k.Timestamp
+	data["version"] = k.Version
+	block.SetLink(data, "parent", k.Parent)
+	block.SetLink(data, "rights", k.Rights)
+	block.SetLink(data, "stakeholders", k.Stakeholders)
+	block.SetLink(data, "content", k.Content)
+	return data
+}
+
+// Encode encodes the ISCN kernel as an ISCN object
+func (k *Kernel) Encode(opts ...block.EncodeOption) (block.IscnObject, error) {
+	return block.Encode(block.CodecISCN, k.SchemaVersion, k.ToDataModel(), opts...)
+}
@@ -0,0 +1,93 @@
+package kernel_test
+
+import (
+	"testing"
+
+	"github.com/likecoin/iscn-ipld/plugin/block"
+	"github.com/likecoin/iscn-ipld/plugin/block/entity"
+	"github.com/likecoin/iscn-ipld/plugin/block/kernel"
+	"github.com/likecoin/iscn-ipld/plugin/internal/blocktest"
+	"github.com/multiformats/go-multihash"
+)
+
+func TestKernelRoundTrip(t *testing.T) {
+	kernel.Register()
+
+	k := kernel.Kernel{
+		ID:           make([]byte, 32),
+		Timestamp:    "2020-01-01T00:00:00Z",
+		Version:      1,
+		Rights:       blocktest.Link(t, block.CodecRights, "rights"),
+		Stakeholders: blocktest.Link(t, block.CodecStakeholders, "stakeholders"),
+		Content:      blocktest.Link(t, block.CodecContent, "content"),
+		Custom:       map[string]interface{}{"extra": []interface{}{"value"}},
+	}
+
+	tests := []struct {
+		name    string
+		version uint64
+		opts    []block.EncodeOption
+		err     bool
+	}{
+		{"v1", 1, nil, false},
+		{"v2", 2, nil, false},
+		{"v3", 3, nil, false},
+		{"SHA2-512", 3, []block.EncodeOption{block.WithMultihash(multihash.SHA2_512, -1)}, false},
+		{"version 0", 0, nil, true},
+		{"not implemented", 4, nil, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			k := k
+			k.SchemaVersion = test.version
+			obj, err := k.Encode(test.opts...)
+			if test.err {
+				if err == nil {
+					t.Fatalf("an error is expected but %s is returned", obj)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			decoded, err := block.Decode(obj.RawData(), obj.Cid())
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			res, err := kernel.FromObject(decoded)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if res.SchemaVersion != test.version {
+				t.Fatalf("version %d is expected but %d is found", test.version, res.SchemaVersion)
+			}
+
+			again, err := res.Encode(test.opts...)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !again.Cid().Equals(obj.Cid()) {
+				t.Fatalf("CID %s is expected but %s is found", obj.Cid(), again.Cid())
+			}
+		})
+	}
+}
+
+func TestFromObjectOfOtherCodec(t *testing.T) {
+	entity.Register()
+
+	obj, err := block.Encode(block.CodecEntity, 1, map[string]interface{}{"id": "llc://alice"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := kernel.FromObject(obj); err == nil {
+		t.Fatal("an error is expected for an entity")
+	}
+}
@@ -0,0 +1,187 @@
+package block_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ipfs/go-cid"
+	"github.com/likecoin/iscn-ipld/plugin/block"
+
+	cbor "github.com/ipfs/go-ipld-cbor"
+)
+
+func TestLinkForm(t *testing.T) {
+	holder := encode(t, block.CodecEntity, 1, map[string]interface{}{"id": "llc://holder"}).Cid()
+	kernel := encode(t, block.CodecISCN, 1, map[string]interface{}{
+		"id":           make([]byte, 32),
+		"timestamp":    "2020-01-01T00:00:00Z",
+		"version":      1,
+		"rights":       cid.NewCidV1(block.CodecRights, holder.Hash()),
+		"stakeholders": cid.NewCidV1(block.CodecStakeholders, holder.Hash()),
+		"content":      cid.NewCidV1(block.CodecContent, holder.Hash()),
+	}).Cid()
+
+	rights := func(version uint64, link interface{}) map[string]interface{} {
+		return map[string]interface{}{
+			block.ContextKey: version,
+			"rights": []interface{}{
+				map[string]interface{}{"holder": link, "type": "License", "terms": link},
+			},
+		}
+	}
+	stakeholders := func(version uint64, link interface{}, footprint interface{}) map[string]interface{} {
+		// The sharing is varint in bytes before schema version 3
+		var sharing interface{} = []byte{100}
+		if version >= 3 {
+			sharing = uint64(100)
+		}
+		return map[string]interface{}{
+			block.ContextKey: version,
+			"stakeholders": []interface{}{
+				map[string]interface{}{
+					"type":        "FootprintStakeholder",
+					"stakeholder": link,
+					"sharing":     sharing,
+					"footprint":   footprint,
+				},
+			},
+		}
+	}
+
+	tests := []struct {
+		name  string
+		codec uint64
+		data  map[string]interface{}
+		err   string
+	}{
+		{"v1 raw bytes", block.CodecRights, rights(1, holder.Bytes()), ""},
+		{"v2 tag 42", block.CodecRights, rights(2, holder), ""},
+		{"v1 tag 42", block.CodecRights, rights(1, holder), "expected as raw bytes"},
+		{"v2 raw bytes", block.CodecRights, rights(2, holder.Bytes()), "expected as CBOR tag 42"},
+		{"v1 footprint raw bytes", block.CodecStakeholders,
+			stakeholders(1, holder.Bytes(), kernel.Bytes()), ""},
+		{"v2 footprint tag 42", block.CodecStakeholders,
+			stakeholders(2, holder, kernel), ""},
+		{"v1 footprint tag 42", block.CodecStakeholders,
+			stakeholders(1, holder.Bytes(), kernel), `"footprint" is expected as raw bytes`},
+		{"v3 footprint raw bytes", block.CodecStakeholders,
+			stakeholders(3, holder, kernel.Bytes()), `"footprint" is expected as CBOR tag 42`},
+		{"v2 footprint URL", block.CodecStakeholders,
+			stakeholders(2, holder, "https://example.com"), ""},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			rawData, err := cbor.DumpObject(test.data)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			obj, _, err := block.DecodeWithCodec(rawData, test.codec)
+			if test.err != "" {
+				if err == nil {
+					t.Fatalf("an error is expected but %s is returned", obj)
+				}
+
+				if !strings.Contains(err.Error(), test.err) {
+					t.Fatalf("%q is expected in the error but %q is found", test.err, err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
+
+func TestLinks(t *testing.T) {
+	holder := link(t, block.CodecEntity, "holder")
+	terms := link(t, block.CodecEntity, "terms")
+	footprint := link(t, block.CodecISCN, "footprint")
+
+	stakeholder := func(entity cid.Cid, footprint interface{}) map[string]interface{} {
+		return map[string]interface{}{
+			"type":        "FootprintStakeholder",
+			"stakeholder": entity,
+			"sharing":     1,
+			"footprint":   footprint,
+		}
+	}
+
+	tests := []struct {
+		name    string
+		codec   uint64
+		version uint64
+		data    map[string]interface{}
+		links   map[string]cid.Cid
+	}{
+		{"rights", block.CodecRights, 1, map[string]interface{}{
+			"rights": []interface{}{
+				map[string]interface{}{"holder": holder, "type": "License", "terms": terms},
+				map[string]interface{}{"holder": terms, "type": "License", "terms": holder},
+			},
+		}, map[string]cid.Cid{
+			"rights/0/holder": holder,
+			"rights/0/terms":  terms,
+			"rights/1/holder": terms,
+			"rights/1/terms":  holder,
+		}},
+		{"stakeholders v2", block.CodecStakeholders, 2, map[string]interface{}{
+			"stakeholders": []interface{}{
+				stakeholder(holder, footprint),
+				stakeholder(terms, "https://example.com"),
+			},
+		}, map[string]cid.Cid{
+			"stakeholders/0/stakeholder": holder,
+			"stakeholders/0/footprint":   footprint,
+			"stakeholders/1/stakeholder": terms,
+		}},
+		{"kernel", block.CodecISCN, 1, map[string]interface{}{
+			"id":           make([]byte, 32),
+			"timestamp":    "2020-01-01T00:00:00Z",
+			"version":      2,
+			"parent":       footprint,
+			"rights":       link(t, block.CodecRights, "rights"),
+			"stakeholders": link(t, block.CodecStakeholders, "stakeholders"),
+			"content":      link(t, block.CodecContent, "content"),
+		}, map[string]cid.Cid{
+			"parent":       footprint,
+			"rights":       link(t, block.CodecRights, "rights"),
+			"stakeholders": link(t, block.CodecStakeholders, "stakeholders"),
+			"content":      link(t, block.CodecContent, "content"),
+		}},
+		{"custom", block.CodecEntity, 1, map[string]interface{}{
+			"id":      "llc://holder",
+			"related": holder,
+			"refs":    []interface{}{terms.Bytes(), "terms"},
+			"meta":    map[string]interface{}{"source": footprint, "note": []byte("abc")},
+		}, map[string]cid.Cid{
+			"related":     holder,
+			"refs/0":      terms,
+			"meta/source": footprint,
+		}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			obj := encode(t, test.codec, test.version, test.data)
+
+			links := map[string]cid.Cid{}
+			for _, l := range obj.Links() {
+				links[l.Name] = l.Cid
+			}
+
+			if len(links) != len(test.links) {
+				t.Fatalf("%d links are expected but %v is found", len(test.links), links)
+			}
+
+			for name, c := range test.links {
+				if !links[name].Equals(c) {
+					t.Fatalf("%s is expected for %q but %s is found", c, name, links[name])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,18 @@
+package block_test
+
+import (
+	"os"
+	"testing"
+
+	"github.com/likecoin/iscn-ipld/plugin/internal/blocktest"
+)
+
+var (
+	encode = blocktest.Encode
+	link   = blocktest.Link
+)
+
+func TestMain(m *testing.M) {
+	blocktest.Register()
+	os.Exit(m.Run())
+}
@@ -0,0 +1,110 @@
+package block
+
+import (
+	"fmt"
+)
+
+// ==================================================
+// Migration
+// ==================================================
+
+// MigrationFunc migrates the data of an ISCN object in IPLD data model, without
+// context, from a version to the next version. The fields dropped or
+// defaulted should be recorded in the report
+type MigrationFunc func(data map[string]interface{}, report *MigrationReport) (map[string]interface{}, error)
+
+var migrations = map[uint64]map[uint64]MigrationFunc{}
+
+// RegisterMigration registers the migration of 'codec' from version 'from' to
+// version 'from' + 1
+func RegisterMigration(codec uint64, from uint64, fn MigrationFunc) {
+	if _, ok := migrations[codec]; !ok {
+		migrations[codec] = map[uint64]MigrationFunc{}
+	}
+	migrations[codec][from] = fn
+}
+
+// MigrateAsIs is the migration for versions which only differ in
+// serialization, e.g. links as CBOR tag 42, so the data is kept as is
+func MigrateAsIs(data map[string]interface{}, _ *MigrationReport) (map[string]interface{}, error) {
+	return data, nil
+}
+
+// MigrationReport records the fields dropped or defaulted during a migration
+// by their paths, the same as the paths of Tree, e.g. "description"
+type MigrationReport struct {
+	Dropped   []string
+	Defaulted []string
+}
+
+// Drop removes the field 'key' from the data and records it if it exists
+func (r *MigrationReport) Drop(data map[string]interface{}, key string) {
+	if _, ok := data[key]; !ok {
+		return
+	}
+
+	delete(data, key)
+	r.Dropped = append(r.Dropped, key)
+}
+
+// Default sets the field 'key' of the data to 'value' and records it if the
+// field does not exist
+func (r *MigrationReport) Default(data map[string]interface{}, key string, value interface{}) {
+	if _, ok := data[key]; ok {
+		return
+	}
+
+	data[key] = value
+	r.Defaulted = append(r.Defaulted, key)
+}
+
+// Upgrade migrates the ISCN object to the schema version 'target' step by step
+// with the registered migrations, and encodes it with the same hash function.
+// The fields dropped or defaulted by the migrations are reported, and the
+// linked objects are not upgraded
+func Upgrade(obj IscnObject, target uint64) (IscnObject, *MigrationReport, error) {
+	prefix := obj.Cid().Prefix()
+	codec := prefix.Codec
+	version := obj.GetVersion()
+	report := &MigrationReport{}
+
+	if target < version {
+		return nil, nil, fmt.Errorf("<%s (v%d)> cannot be downgraded to v%d",
+			obj.GetName(), version, target)
+	}
+
+	if target == version {
+		return obj, report, nil
+	}
+
+	if schemas := factory[codec]; target > (uint64)(len(schemas)) {
+		return nil, nil, fmt.Errorf("<%s (v%d)> is not implemented", schemaNames[codec], target)
+	}
+
+	data, err := obj.ToDataModel()
+	if err != nil {
+		return nil, nil, err
+	}
+	delete(data, ContextKey)
+
+	for ; version < target; version++ {
+		fn, ok := migrations[codec][version]
+		if !ok {
+			return nil, nil, fmt.Errorf("Migration of <%s> from v%d to v%d is not registered",
+				schemaNames[codec], version, version+1)
+		}
+
+		data, err = fn(data, report)
+		if err != nil {
+			return nil, nil, fmt.Errorf("Migration of <%s> from v%d to v%d: %s",
+				schemaNames[codec], version, version+1, err)
+		}
+	}
+
+	res, err := Encode(codec, target, data, WithMultihash(prefix.MhType, prefix.MhLength))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return res, report, nil
+}
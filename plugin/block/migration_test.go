@@ -0,0 +1,168 @@
+package block_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/likecoin/iscn-ipld/plugin/block"
+)
+
+// codecMigration is a codec only registered for testing migrations
+const codecMigration = 0x0300
+
+func init() {
+	block.RegisterSchemaSpecs(
+		codecMigration,
+		"migration",
+		[]block.SchemaSpec{
+			{Fields: []block.FieldSpec{
+				{Key: "id", Type: block.FieldString, Required: true},
+				{Key: "legacy", Type: block.FieldString},
+			}},
+			{Fields: []block.FieldSpec{
+				{Key: "id", Type: block.FieldString, Required: true},
+				{Key: "name", Type: block.FieldString, Required: true},
+			}},
+			{Fields: []block.FieldSpec{
+				{Key: "id", Type: block.FieldString, Required: true},
+				{Key: "name", Type: block.FieldString, Required: true},
+			}},
+		},
+	)
+
+	block.RegisterMigration(codecMigration, 1, func(
+		data map[string]interface{},
+		report *block.MigrationReport,
+	) (map[string]interface{}, error) {
+		report.Drop(data, "legacy")
+		report.Default(data, "name", "unknown")
+		return data, nil
+	})
+	block.RegisterMigration(codecMigration, 2, block.MigrateAsIs)
+}
+
+func TestUpgrade(t *testing.T) {
+	tests := []struct {
+		name      string
+		data      map[string]interface{}
+		target    uint64
+		result    map[string]interface{}
+		dropped   []string
+		defaulted []string
+		err       bool
+	}{
+		{
+			"same version",
+			map[string]interface{}{"id": "a", "legacy": "x"},
+			1,
+			map[string]interface{}{"id": "a", "legacy": "x"},
+			nil,
+			nil,
+			false,
+		},
+		{
+			"drop and default",
+			map[string]interface{}{"id": "a", "legacy": "x"},
+			2,
+			map[string]interface{}{"id": "a", "name": "unknown"},
+			[]string{"legacy"},
+			[]string{"name"},
+			false,
+		},
+		{
+			"nothing to drop",
+			map[string]interface{}{"id": "a"},
+			3,
+			map[string]interface{}{"id": "a", "name": "unknown"},
+			nil,
+			[]string{"name"},
+			false,
+		},
+		{
+			"not implemented",
+			map[string]interface{}{"id": "a"},
+			4,
+			nil,
+			nil,
+			nil,
+			true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			obj := encode(t, codecMigration, 1, test.data)
+
+			res, report, err := block.Upgrade(obj, test.target)
+			if test.err {
+				if err == nil {
+					t.Fatalf("an error is expected but %s is returned", res)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if res.GetVersion() != test.target {
+				t.Fatalf("version %d is expected but %d is found", test.target, res.GetVersion())
+			}
+
+			data, err := res.ToDataModel()
+			if err != nil {
+				t.Fatal(err)
+			}
+			delete(data, block.ContextKey)
+
+			if !reflect.DeepEqual(data, test.result) {
+				t.Fatalf("%v is expected but %v is found", test.result, data)
+			}
+
+			if !reflect.DeepEqual(report.Dropped, test.dropped) {
+				t.Fatalf("dropped %v is expected but %v is found", test.dropped, report.Dropped)
+			}
+
+			if !reflect.DeepEqual(report.Defaulted, test.defaulted) {
+				t.Fatalf("defaulted %v is expected but %v is found", test.defaulted, report.Defaulted)
+			}
+		})
+	}
+}
+
+func TestUpgradeDowngrade(t *testing.T) {
+	obj := encode(t, codecMigration, 2, map[string]interface{}{"id": "a", "name": "b"})
+
+	if _, _, err := block.Upgrade(obj, 1); err == nil {
+		t.Fatal("an error is expected for downgrading")
+	}
+}
+
+func TestUpgradeAsIs(t *testing.T) {
+	obj := encode(t, block.CodecContent, 1, map[string]interface{}{
+		"type":        "article",
+		"version":     1,
+		"fingerprint": "hash://sha256/abc",
+		"title":       "Title",
+	})
+
+	res, report, err := block.Upgrade(obj, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(report.Dropped) != 0 || len(report.Defaulted) != 0 {
+		t.Fatalf("nothing is expected to be reported but %v is found", report)
+	}
+
+	changes, err := block.Diff(obj, res)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, change := range changes {
+		if change.Path != block.ContextKey {
+			t.Fatalf("only the context is expected to be changed but %v is found", change)
+		}
+	}
+}
@@ -0,0 +1,98 @@
+package block_test
+
+import (
+	"testing"
+
+	"github.com/ipfs/go-cid"
+	"github.com/likecoin/iscn-ipld/plugin/block"
+	"github.com/multiformats/go-multihash"
+)
+
+func TestMultihash(t *testing.T) {
+	data := map[string]interface{}{"id": "llc://alice", "name": "Alice"}
+
+	tests := []struct {
+		name     string
+		opts     []block.EncodeOption
+		mhType   uint64
+		mhLength int
+		err      bool
+	}{
+		{"default", nil, multihash.SHA2_256, 32, false},
+		{"SHA2-512", []block.EncodeOption{block.WithMultihash(multihash.SHA2_512, -1)}, multihash.SHA2_512, 64, false},
+		{"SHA3-256", []block.EncodeOption{block.WithMultihash(multihash.SHA3_256, -1)}, multihash.SHA3_256, 32, false},
+		{"BLAKE2b-256", []block.EncodeOption{block.WithMultihash(multihash.BLAKE2B_MIN+31, -1)},
+			multihash.BLAKE2B_MIN + 31, 32, false},
+		{"truncated", []block.EncodeOption{block.WithMultihash(multihash.SHA2_256, 20)}, multihash.SHA2_256, 20, false},
+		{"unknown", []block.EncodeOption{block.WithMultihash(0x9999, -1)}, 0, 0, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			obj, err := block.Encode(block.CodecEntity, 1, data, test.opts...)
+			if test.err {
+				if err == nil {
+					t.Fatalf("an error is expected but %s is returned", obj)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			prefix := obj.Cid().Prefix()
+			if prefix.MhType != test.mhType || prefix.MhLength != test.mhLength {
+				t.Fatalf("multihash %#x of length %d is expected but %#x of length %d is found",
+					test.mhType, test.mhLength, prefix.MhType, prefix.MhLength)
+			}
+
+			// Decode verifies the CID with the hash function of the CID
+			decoded, err := block.Decode(obj.RawData(), obj.Cid())
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !decoded.Cid().Equals(obj.Cid()) {
+				t.Fatalf("%s is expected but %s is found", obj.Cid(), decoded.Cid())
+			}
+
+			// A CID with the same hash function but another digest is rejected
+			other, err := prefix.Sum([]byte("other"))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if _, err := block.Decode(obj.RawData(), other); err == nil {
+				t.Fatalf("an error is expected for %s", other)
+			}
+		})
+	}
+}
+
+func TestDecodeWithOtherHashFunction(t *testing.T) {
+	obj, err := block.Encode(block.CodecEntity, 1, map[string]interface{}{"id": "llc://alice"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The same data is addressed by a CID of another hash function
+	c, err := cid.Prefix{
+		Version:  1,
+		Codec:    block.CodecEntity,
+		MhType:   multihash.BLAKE2B_MIN + 31,
+		MhLength: -1,
+	}.Sum(obj.RawData())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	decoded, err := block.Decode(obj.RawData(), c)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !decoded.Cid().Equals(c) {
+		t.Fatalf("%s is expected but %s is found", c, decoded.Cid())
+	}
+}
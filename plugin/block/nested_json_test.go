@@ -0,0 +1,71 @@
+package block_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/ipfs/go-cid"
+	"github.com/likecoin/iscn-ipld/plugin/block"
+)
+
+func TestMarshalJSONOfNestedObjects(t *testing.T) {
+	holder := link(t, block.CodecEntity, "holder")
+	terms := link(t, block.CodecEntity, "terms")
+	ipfs := func(c cid.Cid) string {
+		s, err := c.StringOfBase('z')
+		if err != nil {
+			t.Fatal(err)
+		}
+		return "/ipfs/" + s
+	}
+
+	tests := []struct {
+		name     string
+		codec    uint64
+		data     map[string]interface{}
+		expected string
+	}{
+		{"rights", block.CodecRights, map[string]interface{}{
+			"rights": []interface{}{
+				map[string]interface{}{
+					"territory": "Global",
+					"period":    map[string]interface{}{"to": "2021-01-01T00:00:00Z", "from": "2020-01-01T00:00:00Z"},
+					"terms":     terms,
+					"type":      "License",
+					"holder":    holder,
+				},
+			},
+		}, fmt.Sprintf(`{"context":"schema/rights-v1","rights":[{"holder":{"/":%q},"type":"License",`+
+			`"terms":{"/":%q},"period":{"from":"2020-01-01T00:00:00Z","to":"2021-01-01T00:00:00Z"},`+
+			`"territory":"Global"}]}`, ipfs(holder), ipfs(terms))},
+		{"stakeholders", block.CodecStakeholders, map[string]interface{}{
+			"stakeholders": []interface{}{
+				map[string]interface{}{
+					"note":        "x",
+					"sharing":     90,
+					"stakeholder": holder,
+					"type":        "Creator",
+				},
+			},
+		}, fmt.Sprintf(`{"context":"schema/stakeholders-v1","stakeholders":[{"type":"Creator",`+
+			`"stakeholder":{"/":%q},"sharing":90,"note":"x"}]}`, ipfs(holder))},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			obj := encode(t, test.codec, 1, test.data)
+
+			// The output is the same every time
+			for i := 0; i < 10; i++ {
+				res, err := obj.MarshalJSON()
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				if string(res) != test.expected {
+					t.Fatalf("%s is expected but %s is found", test.expected, res)
+				}
+			}
+		})
+	}
+}
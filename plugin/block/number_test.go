@@ -0,0 +1,130 @@
+package block_test
+
+import (
+	"testing"
+
+	"github.com/likecoin/iscn-ipld/plugin/block"
+
+	cbor "github.com/ipfs/go-ipld-cbor"
+)
+
+func TestNativeNumber(t *testing.T) {
+	content := func(version uint64, number interface{}) map[string]interface{} {
+		return map[string]interface{}{
+			block.ContextKey: version,
+			"type":           "article",
+			"version":        number,
+			"fingerprint":    "hash://sha256/abc",
+			"title":          "Hello",
+		}
+	}
+
+	dump := func(data interface{}) []byte {
+		rawData, err := cbor.DumpObject(data)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return rawData
+	}
+
+	tests := []struct {
+		name    string
+		rawData []byte
+		value   uint64
+		err     bool
+	}{
+		{"v1 varint", dump(content(1, []byte{1})), 1, false},
+		{"v1 native", dump(content(1, uint64(1))), 0, true},
+		{"v3 native", dump(content(3, uint64(1))), 1, false},
+		{"v3 varint", dump(content(3, []byte{1})), 0, true},
+		{"v3 negative", dump(content(3, int64(-1))), 0, true},
+		{"v3 string", dump(content(3, "1")), 0, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			obj, _, err := block.DecodeWithCodec(test.rawData, block.CodecContent)
+			if test.err {
+				if err == nil {
+					t.Fatalf("an error is expected but %s is returned", obj)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			value, _, err := obj.Resolve([]string{"version"})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if value != test.value {
+				t.Fatalf("%d is expected but %v is found", test.value, value)
+			}
+		})
+	}
+}
+
+func TestConvert(t *testing.T) {
+	v1 := encode(t, block.CodecContent, 1, map[string]interface{}{
+		"type":        "article",
+		"version":     2,
+		"parent":      link(t, block.CodecContent, "parent"),
+		"fingerprint": "hash://sha256/abc",
+		"title":       "Hello",
+	})
+
+	tests := []struct {
+		name    string
+		version uint64
+		native  bool
+		err     bool
+	}{
+		{"v2", 2, false, false},
+		{"v3", 3, true, false},
+		{"v1", 1, false, false},
+		{"not implemented", 4, false, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			obj, err := block.Convert(v1, test.version)
+			if test.err {
+				if err == nil {
+					t.Fatalf("an error is expected but %s is returned", obj)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if obj.GetVersion() != test.version {
+				t.Fatalf("version %d is expected but %d is found", test.version, obj.GetVersion())
+			}
+
+			data := map[string]interface{}{}
+			if err := cbor.DecodeInto(obj.RawData(), &data); err != nil {
+				t.Fatal(err)
+			}
+
+			_, isBytes := data["version"].([]byte)
+			if isBytes == test.native {
+				t.Fatalf("native %t is expected but '%T' is found", test.native, data["version"])
+			}
+
+			// Converting back gives the original object
+			back, err := block.Convert(obj, 1)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !back.Cid().Equals(v1.Cid()) {
+				t.Fatalf("%s is expected but %s is found", v1.Cid(), back.Cid())
+			}
+		})
+	}
+}
@@ -0,0 +1,78 @@
+package block_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/likecoin/iscn-ipld/plugin/block"
+
+	node "github.com/ipfs/go-ipld-format"
+)
+
+func TestResolveCustom(t *testing.T) {
+	entity := link(t, block.CodecEntity, "entity")
+	obj := encode(t, block.CodecEntity, 1, map[string]interface{}{
+		"id":      "llc://alice",
+		"myField": "value",
+		"meta": map[string]interface{}{
+			"tags":  []interface{}{"a", "b"},
+			"link":  entity,
+			"bytes": entity.Bytes(),
+			"plain": []byte{1, 2},
+		},
+	})
+
+	decoded, err := block.Decode(obj.RawData(), obj.Cid())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name  string
+		path  []string
+		value interface{}
+		rest  []string
+		err   bool
+	}{
+		{"string", []string{"myField"}, "value", nil, false},
+		{"array element", []string{"meta", "tags", "1"}, "b", nil, false},
+		{"tag 42 link", []string{"meta", "link"}, &node.Link{Cid: entity}, []string{}, false},
+		{"link with rest", []string{"meta", "link", "name"}, &node.Link{Cid: entity}, []string{"name"}, false},
+		{"CID bytes", []string{"meta", "bytes"}, &node.Link{Cid: entity}, []string{}, false},
+		{"plain bytes", []string{"meta", "plain"}, []byte{1, 2}, nil, false},
+		{"schema field", []string{"id"}, "llc://alice", nil, false},
+		{"missing", []string{"unknown"}, nil, nil, true},
+		{"missing key", []string{"meta", "unknown"}, nil, nil, true},
+		{"out of range", []string{"meta", "tags", "2"}, nil, nil, true},
+		{"not an index", []string{"meta", "tags", "x"}, nil, nil, true},
+		{"past a string", []string{"myField", "x"}, nil, nil, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			value, rest, err := decoded.Resolve(test.path)
+			if test.err {
+				if err == nil {
+					t.Fatalf("an error is expected but %v is returned", value)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if l, ok := value.(*node.Link); ok {
+				if !l.Cid.Equals(test.value.(*node.Link).Cid) {
+					t.Fatalf("%s is expected but %s is found", test.value.(*node.Link).Cid, l.Cid)
+				}
+			} else if !reflect.DeepEqual(value, test.value) {
+				t.Fatalf("%v is expected but %v is found", test.value, value)
+			}
+
+			if len(rest) != len(test.rest) || (len(rest) > 0 && !reflect.DeepEqual(rest, test.rest)) {
+				t.Fatalf("%v is expected as the rest but %v is found", test.rest, rest)
+			}
+		})
+	}
+}
@@ -12,72 +12,55 @@ const (
 
 // Register registers the schema of right block
 func Register() {
-	block.RegisterIscnObjectFactory(
+	block.RegisterSchemaSpecs(
 		block.CodecRight,
 		SchemaName,
-		[]block.CodecFactoryFunc{
-			newSchemaV1,
+		[]block.SchemaSpec{
+			schemaV1,
+			schemaV2,
 		},
 	)
 }
 
 // ==================================================
-// base
+// schemaV1
 // ==================================================
 
-// base is the base struct for right (codec 0x02BD)
-type base struct {
-	*block.Base
+// schemaV1 is the specification of right V1
+var schemaV1 = block.SchemaSpec{
+	Fields: []block.FieldSpec{
+		{Key: "holder", Type: block.FieldLink, Required: true, Codec: block.CodecEntity},
+		{Key: "type", Type: block.FieldString, Required: true}, // TODO use filterd string??
+		{Key: "terms", Type: block.FieldLink, Required: true},
+		{Key: "period", Type: block.FieldObject, Object: timeperiod.SchemaV1Prototype},
+		{Key: "territory", Type: block.FieldString},
+	},
 }
 
-func newBase(version uint64, schema []block.Data) (*base, error) {
-	blockBase, err := block.NewBase(
-		block.CodecRight,
-		SchemaName,
-		version,
-		schema,
-	)
-	if err != nil {
-		return nil, err
-	}
-
-	return &base{
-		Base: blockBase,
-	}, nil
+// SchemaV1Prototype creates a prototype for schemaV1
+func SchemaV1Prototype() block.Codec {
+	res, _ := block.NewCodecFactory(block.CodecRight, SchemaName, 1, schemaV1)()
+	return res
 }
 
 // ==================================================
-// schemaV1
+// schemaV2
 // ==================================================
 
-// schemaV1 represents a right V1
-type schemaV1 struct {
-	*base
-}
-
-var _ block.IscnObject = (*schemaV1)(nil)
-
-func newSchemaV1() (block.Codec, error) {
-	schema := []block.Data{
-		block.NewCid("holder", true, block.CodecEntity),
-		block.NewString("type", true), // TODO use filterd string??
-		block.NewCid("terms", true, 0),
-		block.NewObject("period", false, timeperiod.SchemaV1Prototype),
-		block.NewString("territory", false),
-	}
-
-	timePeriodBase, err := newBase(1, schema)
-	if err != nil {
-		return nil, err
-	}
-
-	return &schemaV1{
-		base: timePeriodBase,
-	}, nil
+// schemaV2 is the specification of right V2, the links are encoded as CBOR
+// tag 42
+var schemaV2 = block.SchemaSpec{
+	Fields: []block.FieldSpec{
+		{Key: "holder", Type: block.FieldLink, Required: true, Codec: block.CodecEntity, Tagged: true},
+		{Key: "type", Type: block.FieldString, Required: true}, // TODO use filterd string??
+		{Key: "terms", Type: block.FieldLink, Required: true, Tagged: true},
+		{Key: "period", Type: block.FieldObject, Object: timeperiod.SchemaV1Prototype},
+		{Key: "territory", Type: block.FieldString},
+	},
 }
 
-// SchemaV1Prototype creates a prototype for schemaV1
-func SchemaV1Prototype() block.Codec {
-	res, _ := newSchemaV1()
+// SchemaV2Prototype creates a prototype for schemaV2
+func SchemaV2Prototype() block.Codec {
+	res, _ := block.NewCodecFactory(block.CodecRight, SchemaName, 2, schemaV2)()
 	return res
 }
@@ -0,0 +1,62 @@
+package right
+
+import (
+	"github.com/ipfs/go-cid"
+	"github.com/likecoin/iscn-ipld/plugin/block"
+	"github.com/likecoin/iscn-ipld/plugin/block/time_period"
+)
+
+// ==================================================
+// Right
+// ==================================================
+
+// Right is the typed struct of a right
+type Right struct {
+	Holder    cid.Cid
+	Type      string
+	Terms     cid.Cid
+	Period    *timeperiod.TimePeriod
+	Territory *string
+
+	Custom map[string]interface{}
+}
+
+// FromDataModel reads a right in IPLD data model
+func FromDataModel(data map[string]interface{}) (*Right, error) {
+	f := block.NewFields(data)
+	res := &Right{
+		Holder:    f.Cid("holder"),
+		Type:      f.String("type"),
+		Terms:     f.Cid("terms"),
+		Territory: f.OptionalString("territory"),
+	}
+	period := f.Object("period")
+	res.Custom = f.Custom()
+
+	if err := f.Err(); err != nil {
+		return nil, err
+	}
+
+	if period != nil {
+		p, err := timeperiod.FromDataModel(period)
+		if err != nil {
+			return nil, err
+		}
+		res.Period = p
+	}
+
+	return res, nil
+}
+
+// ToDataModel converts the right to IPLD data model
+func (r *Right) ToDataModel() map[string]interface{} {
+	data := block.NewDataModel(r.Custom)
+	block.SetLink(data, "holder", r.Holder)
+	data["type"] = r.Type
+	block.SetLink(data, "terms", r.Terms)
+	if r.Period != nil {
+		data["period"] = r.Period.ToDataModel()
+	}
+	block.SetOptionalString(data, "territory", r.Territory)
+	return data
+}
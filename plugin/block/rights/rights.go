@@ -12,64 +12,47 @@ const (
 
 // Register registers the schema of rights block
 func Register() {
-	block.RegisterIscnObjectFactory(
+	block.RegisterSchemaSpecs(
 		block.CodecRights,
 		SchemaName,
-		[]block.CodecFactoryFunc{
-			newSchemaV1,
+		[]block.SchemaSpec{
+			schemaV1,
+			schemaV2,
 		},
 	)
-}
 
-// ==================================================
-// base
-// ==================================================
-
-// base is the base struct for rights (codec 0x0265)
-type base struct {
-	*block.Base
-}
-
-func newBase(version uint64, schema []block.Data) (*base, error) {
-	blockBase, err := block.NewBase(
-		block.CodecRights,
-		SchemaName,
-		version,
-		schema,
-	)
-	if err != nil {
-		return nil, err
-	}
-
-	return &base{
-		Base: blockBase,
-	}, nil
+	block.RegisterMigration(block.CodecRights, 1, block.MigrateAsIs)
 }
 
 // ==================================================
 // schemaV1
 // ==================================================
 
-// schemaV1 represents a rights V1
-type schemaV1 struct {
-	*base
+// schemaV1 is the specification of rights V1
+var schemaV1 = block.SchemaSpec{
+	Fields: []block.FieldSpec{
+		{
+			Key:      "rights",
+			Type:     block.FieldArray,
+			Required: true,
+			Elem:     &block.FieldSpec{Key: "_", Type: block.FieldObject, Required: true, Object: right.SchemaV1Prototype},
+		},
+	},
 }
 
-var _ block.IscnObject = (*schemaV1)(nil)
-
-func newSchemaV1() (block.Codec, error) {
-	prototype := block.NewObject("_", true, right.SchemaV1Prototype)
-
-	schema := []block.Data{
-		block.NewDataArray("rights", true, prototype),
-	}
-
-	rightsBase, err := newBase(1, schema)
-	if err != nil {
-		return nil, err
-	}
+// ==================================================
+// schemaV2
+// ==================================================
 
-	return &schemaV1{
-		base: rightsBase,
-	}, nil
+// schemaV2 is the specification of rights V2, the links are encoded as CBOR
+// tag 42
+var schemaV2 = block.SchemaSpec{
+	Fields: []block.FieldSpec{
+		{
+			Key:      "rights",
+			Type:     block.FieldArray,
+			Required: true,
+			Elem:     &block.FieldSpec{Key: "_", Type: block.FieldObject, Required: true, Object: right.SchemaV2Prototype},
+		},
+	},
 }
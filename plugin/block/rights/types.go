@@ -0,0 +1,66 @@
+package rights
+
+import (
+	"github.com/likecoin/iscn-ipld/plugin/block"
+	"github.com/likecoin/iscn-ipld/plugin/block/right"
+)
+
+// ==================================================
+// Rights
+// ==================================================
+
+// Rights is the typed struct of rights, SchemaVersion is the schema version to
+// encode with
+type Rights struct {
+	SchemaVersion uint64
+
+	Rights []right.Right
+
+	Custom map[string]interface{}
+}
+
+// FromObject reads the rights from an ISCN object
+func FromObject(obj block.IscnObject) (*Rights, error) {
+	f, err := block.ReadFields(obj, block.CodecRights)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &Rights{
+		SchemaVersion: obj.GetVersion(),
+	}
+	rights := f.Objects("rights")
+	res.Custom = f.Custom()
+
+	if err := f.Err(); err != nil {
+		return nil, err
+	}
+
+	res.Rights = make([]right.Right, 0, len(rights))
+	for _, data := range rights {
+		r, err := right.FromDataModel(data)
+		if err != nil {
+			return nil, err
+		}
+		res.Rights = append(res.Rights, *r)
+	}
+
+	return res, nil
+}
+
+// ToDataModel converts the rights to IPLD data model without context
+func (r *Rights) ToDataModel() map[string]interface{} {
+	rights := make([]interface{}, 0, len(r.Rights))
+	for i := range r.Rights {
+		rights = append(rights, r.Rights[i].ToDataModel())
+	}
+
+	data := block.NewDataModel(r.Custom)
+	data["rights"] = rights
+	return data
+}
+
+// Encode encodes the rights as an ISCN object
+func (r *Rights) Encode(opts ...block.EncodeOption) (block.IscnObject, error) {
+	return block.Encode(block.CodecRights, r.SchemaVersion, r.ToDataModel(), opts...)
+}
@@ -0,0 +1,81 @@
+package rights_test
+
+import (
+	"testing"
+
+	"github.com/ipfs/go-cid"
+	"github.com/likecoin/iscn-ipld/plugin/block"
+	"github.com/likecoin/iscn-ipld/plugin/block/right"
+	"github.com/likecoin/iscn-ipld/plugin/block/rights"
+	timeperiod "github.com/likecoin/iscn-ipld/plugin/block/time_period"
+	"github.com/likecoin/iscn-ipld/plugin/internal/blocktest"
+)
+
+func TestRightsRoundTrip(t *testing.T) {
+	rights.Register()
+	right.Register()
+	timeperiod.Register()
+
+	from := "2020-01-01T00:00:00Z"
+	territory := "Global"
+	elems := []right.Right{
+		{
+			Holder: blocktest.Link(t, block.CodecEntity, "holder"),
+			Type:   "License",
+			Terms:  blocktest.Link(t, cid.Raw, "terms"),
+		},
+		{
+			Holder:    blocktest.Link(t, block.CodecEntity, "holder"),
+			Type:      "License",
+			Terms:     blocktest.Link(t, cid.Raw, "terms"),
+			Period:    &timeperiod.TimePeriod{From: &from},
+			Territory: &territory,
+			Custom:    map[string]interface{}{"extra": "value"},
+		},
+	}
+
+	tests := []struct {
+		name   string
+		rights rights.Rights
+		err    bool
+	}{
+		{"v1", rights.Rights{SchemaVersion: 1, Rights: elems}, false},
+		{"v2", rights.Rights{SchemaVersion: 2, Rights: elems}, false},
+		{"empty", rights.Rights{SchemaVersion: 2, Rights: []right.Right{}}, false},
+		{"version 0", rights.Rights{Rights: elems}, true},
+		{"missing holder", rights.Rights{
+			SchemaVersion: 2,
+			Rights:        []right.Right{{Type: "License", Terms: blocktest.Link(t, cid.Raw, "terms")}},
+		}, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			obj, err := test.rights.Encode()
+			if test.err {
+				if err == nil {
+					t.Fatalf("an error is expected but %s is returned", obj)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			res, err := rights.FromObject(obj)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			again, err := res.Encode()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !again.Cid().Equals(obj.Cid()) {
+				t.Fatalf("CID %s is expected but %s is found", obj.Cid(), again.Cid())
+			}
+		})
+	}
+}
@@ -0,0 +1,151 @@
+package block_test
+
+import (
+	"testing"
+
+	"github.com/ipfs/go-cid"
+	"github.com/likecoin/iscn-ipld/plugin/block"
+)
+
+func TestStat(t *testing.T) {
+	holder := link(t, block.CodecEntity, "holder")
+	terms := link(t, block.CodecEntity, "terms")
+
+	tests := []struct {
+		name     string
+		codec    uint64
+		data     map[string]interface{}
+		numLinks int
+	}{
+		{"without links", block.CodecEntity, map[string]interface{}{
+			"id": "llc://entity",
+		}, 0},
+		{"with links", block.CodecRights, map[string]interface{}{
+			"rights": []interface{}{
+				map[string]interface{}{"holder": holder, "type": "License", "terms": terms},
+			},
+		}, 2},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			obj := encode(t, test.codec, 1, test.data)
+
+			stat, err := obj.Stat()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if stat.Hash != obj.Cid().String() {
+				t.Fatalf("hash %s is expected but %s is found", obj.Cid(), stat.Hash)
+			}
+
+			if stat.NumLinks != test.numLinks {
+				t.Fatalf("%d links are expected but %d is found", test.numLinks, stat.NumLinks)
+			}
+
+			if stat.BlockSize != len(obj.RawData()) {
+				t.Fatalf("block size %d is expected but %d is found",
+					len(obj.RawData()), stat.BlockSize)
+			}
+
+			linksSize := 0
+			for _, link := range obj.Links() {
+				linksSize += len(link.Cid.Bytes())
+			}
+
+			if stat.LinksSize != linksSize {
+				t.Fatalf("links size %d is expected but %d is found", linksSize, stat.LinksSize)
+			}
+
+			if stat.DataSize != stat.BlockSize-linksSize {
+				t.Fatalf("data size %d is expected but %d is found",
+					stat.BlockSize-linksSize, stat.DataSize)
+			}
+
+			// The sizes of the linked blocks are unknown
+			size, err := obj.Size()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if size != uint64(len(obj.RawData())) || stat.CumulativeSize != int(size) {
+				t.Fatalf("cumulative size %d is expected but %d (%d) is found",
+					len(obj.RawData()), stat.CumulativeSize, size)
+			}
+		})
+	}
+}
+
+func TestLinkSize(t *testing.T) {
+	holder := encode(t, block.CodecEntity, 1, map[string]interface{}{"id": "llc://holder"})
+	terms := encode(t, block.CodecEntity, 1, map[string]interface{}{"id": "llc://terms"})
+
+	holderSize, err := holder.Size()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name      string
+		linkSizes map[cid.Cid]uint64
+		linked    uint64
+	}{
+		{"unknown link sizes", nil, 0},
+		{"one link size", map[cid.Cid]uint64{holder.Cid(): holderSize}, holderSize},
+		{"all link sizes", map[cid.Cid]uint64{
+			holder.Cid(): holderSize,
+			terms.Cid():  100,
+		}, holderSize + 100},
+		{"unrelated link size", map[cid.Cid]uint64{
+			encode(t, block.CodecEntity, 1, map[string]interface{}{"id": "llc://other"}).Cid(): 100,
+		}, 0},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			obj := encode(t, block.CodecRights, 2, map[string]interface{}{
+				"rights": []interface{}{
+					map[string]interface{}{
+						"holder": holder.Cid(),
+						"type":   "License",
+						"terms":  terms.Cid(),
+					},
+				},
+			})
+
+			for c, size := range test.linkSizes {
+				obj.SetLinkSize(c, size)
+			}
+
+			for _, node := range []block.IscnObject{obj, obj.Copy().(block.IscnObject)} {
+				stat, err := node.Stat()
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				if stat.NumLinks != 2 {
+					t.Fatalf("2 links are expected but %d is found", stat.NumLinks)
+				}
+
+				if stat.BlockSize != len(obj.RawData()) {
+					t.Fatalf("block size %d is expected but %d is found",
+						len(obj.RawData()), stat.BlockSize)
+				}
+
+				expected := len(obj.RawData()) + int(test.linked)
+				if stat.CumulativeSize != expected {
+					t.Fatalf("cumulative size %d is expected but %d is found",
+						expected, stat.CumulativeSize)
+				}
+
+				for _, link := range node.Links() {
+					if link.Size != test.linkSizes[link.Cid] {
+						t.Fatalf("size %d of %q is expected but %d is found",
+							test.linkSizes[link.Cid], link.Name, link.Size)
+					}
+				}
+			}
+		})
+	}
+}
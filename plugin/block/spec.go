@@ -0,0 +1,206 @@
+package block
+
+import (
+	"fmt"
+
+	node "github.com/ipfs/go-ipld-format"
+)
+
+// ==================================================
+// SchemaSpec
+// ==================================================
+
+// FieldType is a enum type for the type of a field
+type FieldType int
+
+const (
+	// FieldString represents a string, optionally filtered by FieldSpec.Filter
+	FieldString FieldType = iota
+
+	// FieldNumber represents a number of FieldSpec.Number
+	FieldNumber
+
+	// FieldTimestamp represents a timestamp
+	FieldTimestamp
+
+	// FieldLink represents a link to an object of FieldSpec.Codec, any codec
+	// is accepted if it is 0
+	FieldLink
+
+	// FieldObject represents a nested object created by FieldSpec.Object
+	FieldObject
+
+	// FieldArray represents an array of which the elements are FieldSpec.Elem
+	FieldArray
+
+	// FieldCustom represents a field of the data handler created by
+	// FieldSpec.Handler
+	FieldCustom
+)
+
+// FieldSpec is the specification of a field of an ISCN object
+type FieldSpec struct {
+	Key      string
+	Type     FieldType
+	Required bool
+
+	Filter  []string            // FieldString
+	Number  NumberType          // FieldNumber
+	Native  bool                // FieldNumber encoded as CBOR integer
+	Codec   uint64              // FieldLink
+	Tagged  bool                // FieldLink encoded as CBOR tag 42
+	Object  ObjectPrototypeFunc // FieldObject
+	Elem    *FieldSpec          // FieldArray
+	Handler func() Data         // FieldCustom
+}
+
+// NewHandler creates the data handler of the field
+func (f FieldSpec) NewHandler() (Data, error) {
+	switch f.Type {
+	case FieldString:
+		if f.Filter != nil {
+			return NewStringWithFilter(f.Key, f.Required, f.Filter), nil
+		}
+		return NewString(f.Key, f.Required), nil
+
+	case FieldNumber:
+		if f.Native {
+			return NewNativeNumber(f.Key, f.Required, f.Number), nil
+		}
+		return NewNumber(f.Key, f.Required, f.Number), nil
+
+	case FieldTimestamp:
+		return NewTimestamp(f.Key, f.Required), nil
+
+	case FieldLink:
+		if f.Tagged {
+			return NewTaggedCid(f.Key, f.Required, f.Codec), nil
+		}
+		return NewCid(f.Key, f.Required, f.Codec), nil
+
+	case FieldObject:
+		if f.Object == nil {
+			return nil, fmt.Errorf("FieldSpec: %q has no object prototype", f.Key)
+		}
+		return NewObject(f.Key, f.Required, f.Object), nil
+
+	case FieldArray:
+		if f.Elem == nil {
+			return nil, fmt.Errorf("FieldSpec: %q has no element", f.Key)
+		}
+
+		elem, err := f.Elem.NewHandler()
+		if err != nil {
+			return nil, err
+		}
+		return NewDataArray(f.Key, f.Required, elem), nil
+
+	case FieldCustom:
+		if f.Handler == nil {
+			return nil, fmt.Errorf("FieldSpec: %q has no data handler", f.Key)
+		}
+
+		handler := f.Handler()
+		if handler.GetKey() != f.Key {
+			return nil, fmt.Errorf("FieldSpec: %q is expected but the key of data handler is %q",
+				f.Key, handler.GetKey())
+		}
+		return handler, nil
+	}
+
+	return nil, fmt.Errorf("FieldSpec: unknown type %d of %q", f.Type, f.Key)
+}
+
+// SchemaSpec is the specification of a schema version of an ISCN object. The
+// optional validator is run after the data is set or decoded, the data
+// handlers are retrieved by Base.GetHandler. The optional String and Loggable
+// override the output of Base, e.g. to show an ID
+type SchemaSpec struct {
+	Fields   []FieldSpec
+	Validate func(*Base) error
+	String   func(*Base) string
+	Loggable func(*Base, map[string]interface{})
+}
+
+// NewCodecFactory creates the factory function of the ISCN object from the
+// specification of the schema 'version'
+func NewCodecFactory(codec uint64, name string, version uint64, spec SchemaSpec) CodecFactoryFunc {
+	return func() (Codec, error) {
+		schema := make([]Data, 0, len(spec.Fields))
+		for _, field := range spec.Fields {
+			handler, err := field.NewHandler()
+			if err != nil {
+				return nil, err
+			}
+			schema = append(schema, handler)
+		}
+
+		blockBase, err := NewBase(codec, name, version, schema)
+		if err != nil {
+			return nil, err
+		}
+
+		return newSpecObject(blockBase, spec), nil
+	}
+}
+
+// RegisterSchemaSpecs registers the ISCN object of which the schema versions
+// are built from the specifications, the first one is version 1
+func RegisterSchemaSpecs(codec uint64, name string, specs []SchemaSpec) {
+	factories := make([]CodecFactoryFunc, 0, len(specs))
+	for i, spec := range specs {
+		factories = append(factories, NewCodecFactory(codec, name, uint64(i+1), spec))
+	}
+
+	RegisterIscnObjectFactory(codec, name, factories)
+}
+
+// ==================================================
+// specObject
+// ==================================================
+
+// specObject is the ISCN object built from a schema specification
+type specObject struct {
+	*Base
+
+	spec SchemaSpec
+}
+
+var _ Codec = (*specObject)(nil)
+
+func newSpecObject(blockBase *Base, spec SchemaSpec) *specObject {
+	obj := &specObject{
+		Base: blockBase,
+		spec: spec,
+	}
+
+	if spec.Validate != nil {
+		blockBase.SetValidator(func() error {
+			return spec.Validate(blockBase)
+		})
+	}
+
+	return obj
+}
+
+// Copy returns a deep copy of the ISCN object
+func (o *specObject) Copy() node.Node {
+	return newSpecObject(o.Base.Copy().(*Base), o.spec)
+}
+
+// Loggable returns a map the type of IPLD Link
+func (o *specObject) Loggable() map[string]interface{} {
+	l := o.Base.Loggable()
+	if o.spec.Loggable != nil {
+		o.spec.Loggable(o.Base, l)
+	}
+	return l
+}
+
+// String is a helper for output
+func (o *specObject) String() string {
+	if o.spec.String != nil {
+		return o.spec.String(o.Base)
+	}
+	return o.Base.String()
+}
@@ -0,0 +1,165 @@
+package block_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/likecoin/iscn-ipld/plugin/block"
+)
+
+func TestFieldSpecNewHandler(t *testing.T) {
+	tests := []struct {
+		name  string
+		field block.FieldSpec
+		err   bool
+	}{
+		{"string", block.FieldSpec{Key: "a", Type: block.FieldString}, false},
+		{"filtered string", block.FieldSpec{Key: "a", Type: block.FieldString, Filter: []string{"x"}}, false},
+		{"number", block.FieldSpec{Key: "a", Type: block.FieldNumber, Number: block.Uint32T}, false},
+		{"native number", block.FieldSpec{Key: "a", Type: block.FieldNumber, Native: true}, false},
+		{"timestamp", block.FieldSpec{Key: "a", Type: block.FieldTimestamp}, false},
+		{"link", block.FieldSpec{Key: "a", Type: block.FieldLink, Tagged: true}, false},
+		{"array", block.FieldSpec{Key: "a", Type: block.FieldArray,
+			Elem: &block.FieldSpec{Key: "_", Type: block.FieldString}}, false},
+		{"custom", block.FieldSpec{Key: "a", Type: block.FieldCustom,
+			Handler: func() block.Data { return block.NewString("a", false) }}, false},
+		{"object without prototype", block.FieldSpec{Key: "a", Type: block.FieldObject}, true},
+		{"array without element", block.FieldSpec{Key: "a", Type: block.FieldArray}, true},
+		{"invalid element", block.FieldSpec{Key: "a", Type: block.FieldArray,
+			Elem: &block.FieldSpec{Key: "_", Type: block.FieldObject}}, true},
+		{"custom without handler", block.FieldSpec{Key: "a", Type: block.FieldCustom}, true},
+		{"custom of another key", block.FieldSpec{Key: "a", Type: block.FieldCustom,
+			Handler: func() block.Data { return block.NewString("b", false) }}, true},
+		{"unknown type", block.FieldSpec{Key: "a", Type: block.FieldType(99)}, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			handler, err := test.field.NewHandler()
+			if test.err {
+				if err == nil {
+					t.Fatalf("an error is expected but '%T' is returned", handler)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if handler.GetKey() != test.field.Key {
+				t.Fatalf("%q is expected but %q is found", test.field.Key, handler.GetKey())
+			}
+		})
+	}
+}
+
+func TestSchemaSpecValidate(t *testing.T) {
+	spec := block.SchemaSpec{
+		Fields: []block.FieldSpec{
+			{Key: "from", Type: block.FieldNumber, Number: block.Uint64T, Native: true, Required: true},
+			{Key: "to", Type: block.FieldNumber, Number: block.Uint64T, Native: true, Required: true},
+		},
+		Validate: func(b *block.Base) error {
+			from, err := b.GetHandler("from").(*block.Number).GetUint64()
+			if err != nil {
+				return err
+			}
+
+			to, err := b.GetHandler("to").(*block.Number).GetUint64()
+			if err != nil {
+				return err
+			}
+
+			if from > to {
+				return fmt.Errorf("from is after to")
+			}
+			return nil
+		},
+	}
+
+	tests := []struct {
+		name string
+		data map[string]interface{}
+		err  bool
+	}{
+		{"valid", map[string]interface{}{"from": 1, "to": 2}, false},
+		{"invalid", map[string]interface{}{"from": 2, "to": 1}, true},
+		{"missing", map[string]interface{}{"from": 1}, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			obj, err := block.NewCodecFactory(0x0400, "spec", 1, spec)()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			err = obj.SetData(test.data)
+			if test.err {
+				if err == nil {
+					t.Fatal("an error is expected")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
+
+func TestSchemaSpecHooks(t *testing.T) {
+	spec := block.SchemaSpec{
+		Fields: []block.FieldSpec{{Key: "name", Type: block.FieldString, Required: true}},
+	}
+	hooked := spec
+	hooked.String = func(b *block.Base) string {
+		return "<" + b.GetHandler("name").(*block.String).Get() + ">"
+	}
+	hooked.Loggable = func(b *block.Base, l map[string]interface{}) {
+		l["name"] = b.GetHandler("name").(*block.String).Get()
+	}
+
+	tests := []struct {
+		name     string
+		spec     block.SchemaSpec
+		str      string
+		loggable map[string]interface{}
+	}{
+		{"default", spec, "<spec (v1)>", map[string]interface{}{"type": "spec", "version": uint64(1)}},
+		{"hooks", hooked, "<alice>", map[string]interface{}{"type": "spec", "version": uint64(1), "name": "alice"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			obj, err := block.NewCodecFactory(0x0400, "spec", 1, test.spec)()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if err := obj.SetData(map[string]interface{}{"name": "alice"}); err != nil {
+				t.Fatal(err)
+			}
+
+			if s := obj.String(); s != test.str {
+				t.Fatalf("%q is expected but %q is found", test.str, s)
+			}
+
+			l := obj.Loggable()
+			if len(l) != len(test.loggable) {
+				t.Fatalf("%v is expected but %v is found", test.loggable, l)
+			}
+			for key, value := range test.loggable {
+				if l[key] != value {
+					t.Fatalf("%v is expected but %v is found", test.loggable, l)
+				}
+			}
+
+			if s := obj.Copy().String(); s != test.str {
+				t.Fatalf("%q is expected for the copy but %q is found", test.str, s)
+			}
+		})
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 
 	"github.com/ipfs/go-cid"
+	node "github.com/ipfs/go-ipld-format"
 	"github.com/likecoin/iscn-ipld/plugin/block"
 	"gitlab.com/c0b/go-ordered-json"
 )
@@ -51,6 +52,13 @@ func (d *Type) Prototype() block.Data {
 	return NewType()
 }
 
+// Copy creates a deep copy of Type
+func (d *Type) Copy() block.Data {
+	return &Type{
+		String: d.String.Copy().(*block.String),
+	}
+}
+
 // ==================================================
 // Footprint
 // ==================================================
@@ -59,22 +67,48 @@ func (d *Type) Prototype() block.Data {
 type Footprint struct {
 	*block.DataBase
 
-	handler block.Data
+	handler  block.Data
+	isTagged bool
 }
 
 var _ block.Data = (*Footprint)(nil)
 
-// NewFootprint creates a footprint data handler
+// NewFootprint creates a footprint data handler, the link is encoded as raw
+// bytes
 func NewFootprint() *Footprint {
 	return &Footprint{
 		DataBase: block.NewDataBase("footprint", false),
 	}
 }
 
+// NewTaggedFootprint creates a footprint data handler, the link is encoded as
+// CBOR tag 42
+func NewTaggedFootprint() *Footprint {
+	return &Footprint{
+		DataBase: block.NewDataBase("footprint", false),
+		isTagged: true,
+	}
+}
+
 // Prototype creates a protype Footprint
 func (d *Footprint) Prototype() block.Data {
 	return &Footprint{
 		DataBase: d.DataBase.Prototype(),
+		isTagged: d.isTagged,
+	}
+}
+
+// Copy creates a deep copy of Footprint
+func (d *Footprint) Copy() block.Data {
+	var handler block.Data
+	if d.handler != nil {
+		handler = d.handler.Copy()
+	}
+
+	return &Footprint{
+		DataBase: d.DataBase.Copy(),
+		handler:  handler,
+		isTagged: d.isTagged,
 	}
 }
 
@@ -86,7 +120,7 @@ func (d *Footprint) Set(data interface{}) error {
 
 	switch data.(type) {
 	case cid.Cid:
-		d.handler = block.NewCid(d.GetKey(), d.IsRequired(), block.CodecISCN)
+		d.handler = d.newCid()
 	case string:
 		// TODO URL handler
 		d.handler = block.NewString(d.GetKey(), d.IsRequired())
@@ -113,8 +147,8 @@ func (d *Footprint) Decode(data interface{}, m *map[string]interface{}) error {
 	}
 
 	switch data.(type) {
-	case []uint8:
-		d.handler = block.NewCid(d.GetKey(), d.IsRequired(), block.CodecISCN)
+	case []uint8, cid.Cid:
+		d.handler = d.newCid()
 	case string:
 		// TODO URL handler
 		d.handler = block.NewString(d.GetKey(), d.IsRequired())
@@ -134,7 +168,42 @@ func (d *Footprint) ToJSON(om *ordered.OrderedMap) error {
 	return d.handler.ToJSON(om)
 }
 
+// ToDataModel prepares the data for IPLD data model
+func (d *Footprint) ToDataModel(m *map[string]interface{}) error {
+	return d.handler.ToDataModel(m)
+}
+
 // Resolve resolves the link
 func (d *Footprint) Resolve(path []string) (interface{}, []string, error) {
 	return d.handler.Resolve(path)
 }
+
+// JSONSchema describes the footprint as a link to ISCN kernel or a string
+func (d *Footprint) JSONSchema() *ordered.OrderedMap {
+	link := d.newCid().(block.JSONSchemaData).JSONSchema()
+
+	str := ordered.NewOrderedMap()
+	str.Set("type", "string")
+
+	om := ordered.NewOrderedMap()
+	om.Set("anyOf", []interface{}{link, str})
+	return om
+}
+
+// Tree lists all paths under the footprint
+func (d *Footprint) Tree() []string {
+	return d.handler.Tree()
+}
+
+// Links returns the link of the footprint if it is a link
+func (d *Footprint) Links() []*node.Link {
+	return d.handler.Links()
+}
+
+// newCid creates the handler for the footprint link
+func (d *Footprint) newCid() block.Data {
+	if d.isTagged {
+		return block.NewTaggedCid(d.GetKey(), d.IsRequired(), block.CodecISCN)
+	}
+	return block.NewCid(d.GetKey(), d.IsRequired(), block.CodecISCN)
+}
@@ -13,94 +13,93 @@ const (
 
 // Register registers the schema of stakeholder block
 func Register() {
-	block.RegisterIscnObjectFactory(
+	block.RegisterSchemaSpecs(
 		block.CodecStakeholder,
 		SchemaName,
-		[]block.CodecFactoryFunc{
-			newSchemaV1,
+		[]block.SchemaSpec{
+			schemaV1,
+			schemaV2,
+			schemaV3,
 		},
 	)
 }
 
 // ==================================================
-// base
+// schemaV1
 // ==================================================
 
-// base is the base struct for stakeholder (codec 0x02D1)
-type base struct {
-	*block.Base
+// schemaV1 is the specification of stakeholder V1
+var schemaV1 = block.SchemaSpec{
+	Fields: []block.FieldSpec{
+		{Key: "type", Type: block.FieldCustom, Handler: func() block.Data { return NewType() }},
+		{Key: "stakeholder", Type: block.FieldLink, Required: true, Codec: block.CodecEntity},
+		{Key: "sharing", Type: block.FieldNumber, Required: true, Number: block.Uint32T},
+		{Key: "footprint", Type: block.FieldCustom, Handler: func() block.Data { return NewFootprint() }},
+	},
+	Validate: validate,
 }
 
-func newBase(version uint64, schema []block.Data) (*base, error) {
-	blockBase, err := block.NewBase(
-		block.CodecStakeholder,
-		SchemaName,
-		version,
-		schema,
-	)
-	if err != nil {
-		return nil, err
-	}
-
-	return &base{
-		Base: blockBase,
-	}, nil
+// SchemaV1Prototype creates a prototype for schemaV1
+func SchemaV1Prototype() block.Codec {
+	res, _ := block.NewCodecFactory(block.CodecStakeholder, SchemaName, 1, schemaV1)()
+	return res
 }
 
 // ==================================================
-// schemaV1
+// schemaV2
 // ==================================================
 
-// schemaV1 represents a stakeholder V1
-type schemaV1 struct {
-	*base
-
-	ty        *Type
-	footprint *Footprint
+// schemaV2 is the specification of stakeholder V2, the links are encoded as
+// CBOR tag 42
+var schemaV2 = block.SchemaSpec{
+	Fields: []block.FieldSpec{
+		{Key: "type", Type: block.FieldCustom, Handler: func() block.Data { return NewType() }},
+		{Key: "stakeholder", Type: block.FieldLink, Required: true, Codec: block.CodecEntity, Tagged: true},
+		{Key: "sharing", Type: block.FieldNumber, Required: true, Number: block.Uint32T},
+		{Key: "footprint", Type: block.FieldCustom, Handler: func() block.Data { return NewTaggedFootprint() }},
+	},
+	Validate: validate,
 }
 
-var _ block.IscnObject = (*schemaV1)(nil)
-
-func newSchemaV1() (block.Codec, error) {
-	ty := NewType()
-	footprint := NewFootprint()
-
-	schema := []block.Data{
-		ty,
-		block.NewCid("stakeholder", true, block.CodecEntity),
-		block.NewNumber("sharing", true, block.Uint32T),
-		footprint,
-	}
-
-	stakeholderBase, err := newBase(1, schema)
-	if err != nil {
-		return nil, err
-	}
+// SchemaV2Prototype creates a prototype for schemaV2
+func SchemaV2Prototype() block.Codec {
+	res, _ := block.NewCodecFactory(block.CodecStakeholder, SchemaName, 2, schemaV2)()
+	return res
+}
 
-	obj := schemaV1{
-		base:      stakeholderBase,
-		ty:        ty,
-		footprint: footprint,
-	}
-	stakeholderBase.SetValidator(obj.Validate)
+// ==================================================
+// schemaV3
+// ==================================================
 
-	return &obj, nil
+// schemaV3 is the specification of stakeholder V3, the links are encoded as
+// CBOR tag 42 and the numbers are encoded as CBOR integers
+var schemaV3 = block.SchemaSpec{
+	Fields: []block.FieldSpec{
+		{Key: "type", Type: block.FieldCustom, Handler: func() block.Data { return NewType() }},
+		{Key: "stakeholder", Type: block.FieldLink, Required: true, Codec: block.CodecEntity, Tagged: true},
+		{Key: "sharing", Type: block.FieldNumber, Required: true, Number: block.Uint32T, Native: true},
+		{Key: "footprint", Type: block.FieldCustom, Handler: func() block.Data { return NewTaggedFootprint() }},
+	},
+	Validate: validate,
 }
 
-// SchemaV1Prototype creates a prototype for schemaV1
-func SchemaV1Prototype() block.Codec {
-	res, _ := newSchemaV1()
+// SchemaV3Prototype creates a prototype for schemaV3
+func SchemaV3Prototype() block.Codec {
+	res, _ := block.NewCodecFactory(block.CodecStakeholder, SchemaName, 3, schemaV3)()
 	return res
 }
 
-// Validate the data
-func (o *schemaV1) Validate() error {
-	if o.ty.Get() == footprint {
-		if !o.footprint.IsDefined() {
+// validate the footprint against the type of stakeholder
+func validate(b *block.Base) error {
+	ty := b.GetHandler("type").(*Type)
+	fp := b.GetHandler("footprint").(*Footprint)
+
+	if ty.Get() == footprint {
+		if !fp.IsDefined() {
 			return fmt.Errorf("Footprint is missed")
 		}
 	} else {
-		if o.footprint.IsDefined() {
+		if fp.IsDefined() {
 			return fmt.Errorf("Footprint should not be set as this is not a footprint stakeholder")
 		}
 	}
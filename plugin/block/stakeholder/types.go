@@ -0,0 +1,52 @@
+package stakeholder
+
+import (
+	"github.com/ipfs/go-cid"
+	"github.com/likecoin/iscn-ipld/plugin/block"
+)
+
+// ==================================================
+// Stakeholder
+// ==================================================
+
+// Stakeholder is the typed struct of a stakeholder. The footprint is either
+// a link to an ISCN kernel or a URL
+type Stakeholder struct {
+	Type         string
+	Stakeholder  cid.Cid
+	Sharing      uint32
+	Footprint    cid.Cid
+	FootprintURL *string
+
+	Custom map[string]interface{}
+}
+
+// FromDataModel reads a stakeholder in IPLD data model
+func FromDataModel(data map[string]interface{}) (*Stakeholder, error) {
+	f := block.NewFields(data)
+	res := &Stakeholder{
+		Type:        f.String("type"),
+		Stakeholder: f.Cid("stakeholder"),
+		Sharing:     f.Uint32("sharing"),
+	}
+	res.Footprint, res.FootprintURL = f.Link("footprint")
+	res.Custom = f.Custom()
+
+	if err := f.Err(); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// ToDataModel converts the stakeholder to IPLD data model
+func (s *Stakeholder) ToDataModel() map[string]interface{} {
+	data := block.NewDataModel(s.Custom)
+	if s.Type != "" {
+		data["type"] = s.Type
+	}
+	block.SetLink(data, "stakeholder", s.Stakeholder)
+	data["sharing"] = s.Sharing
+	block.SetLink(data, "footprint", s.Footprint)
+	block.SetOptionalString(data, "footprint", s.FootprintURL)
+	return data
+}
@@ -12,64 +12,66 @@ const (
 
 // Register registers the schema of stakeholders block
 func Register() {
-	block.RegisterIscnObjectFactory(
+	block.RegisterSchemaSpecs(
 		block.CodecStakeholders,
 		SchemaName,
-		[]block.CodecFactoryFunc{
-			newSchemaV1,
+		[]block.SchemaSpec{
+			schemaV1,
+			schemaV2,
+			schemaV3,
 		},
 	)
+
+	block.RegisterMigration(block.CodecStakeholders, 1, block.MigrateAsIs)
+	block.RegisterMigration(block.CodecStakeholders, 2, block.MigrateAsIs)
 }
 
 // ==================================================
-// base
+// schemaV1
 // ==================================================
 
-// base is the base struct for stakeholders (codec 0x0266)
-type base struct {
-	*block.Base
-}
-
-func newBase(version uint64, schema []block.Data) (*base, error) {
-	blockBase, err := block.NewBase(
-		block.CodecStakeholders,
-		SchemaName,
-		version,
-		schema,
-	)
-	if err != nil {
-		return nil, err
-	}
-
-	return &base{
-		Base: blockBase,
-	}, nil
+// schemaV1 is the specification of stakeholders V1
+var schemaV1 = block.SchemaSpec{
+	Fields: []block.FieldSpec{
+		{
+			Key:      "stakeholders",
+			Type:     block.FieldArray,
+			Required: true,
+			Elem:     &block.FieldSpec{Key: "_", Type: block.FieldObject, Required: true, Object: stakeholder.SchemaV1Prototype},
+		},
+	},
 }
 
 // ==================================================
-// schemaV1
+// schemaV2
 // ==================================================
 
-// schemaV1 represents a stakeholders V1
-type schemaV1 struct {
-	*base
+// schemaV2 is the specification of stakeholders V2, the links are encoded as
+// CBOR tag 42
+var schemaV2 = block.SchemaSpec{
+	Fields: []block.FieldSpec{
+		{
+			Key:      "stakeholders",
+			Type:     block.FieldArray,
+			Required: true,
+			Elem:     &block.FieldSpec{Key: "_", Type: block.FieldObject, Required: true, Object: stakeholder.SchemaV2Prototype},
+		},
+	},
 }
 
-var _ block.IscnObject = (*schemaV1)(nil)
-
-func newSchemaV1() (block.Codec, error) {
-	prototype := block.NewObject("_", true, stakeholder.SchemaV1Prototype)
-
-	schema := []block.Data{
-		block.NewDataArray("stakeholders", true, prototype),
-	}
-
-	stakeholdersBase, err := newBase(1, schema)
-	if err != nil {
-		return nil, err
-	}
+// ==================================================
+// schemaV3
+// ==================================================
 
-	return &schemaV1{
-		base: stakeholdersBase,
-	}, nil
+// schemaV3 is the specification of stakeholders V3, the links are encoded as
+// CBOR tag 42 and the numbers are encoded as CBOR integers
+var schemaV3 = block.SchemaSpec{
+	Fields: []block.FieldSpec{
+		{
+			Key:      "stakeholders",
+			Type:     block.FieldArray,
+			Required: true,
+			Elem:     &block.FieldSpec{Key: "_", Type: block.FieldObject, Required: true, Object: stakeholder.SchemaV3Prototype},
+		},
+	},
 }
@@ -0,0 +1,66 @@
+package stakeholders
+
+import (
+	"github.com/likecoin/iscn-ipld/plugin/block"
+	"github.com/likecoin/iscn-ipld/plugin/block/stakeholder"
+)
+
+// ==================================================
+// Stakeholders
+// ==================================================
+
+// Stakeholders is the typed struct of stakeholders, SchemaVersion is the schema
+// version to encode with
+type Stakeholders struct {
+	SchemaVersion uint64
+
+	Stakeholders []stakeholder.Stakeholder
+
+	Custom map[string]interface{}
+}
+
+// FromObject reads the stakeholders from an ISCN object
+func FromObject(obj block.IscnObject) (*Stakeholders, error) {
+	f, err := block.ReadFields(obj, block.CodecStakeholders)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &Stakeholders{
+		SchemaVersion: obj.GetVersion(),
+	}
+	stakeholders := f.Objects("stakeholders")
+	res.Custom = f.Custom()
+
+	if err := f.Err(); err != nil {
+		return nil, err
+	}
+
+	res.Stakeholders = make([]stakeholder.Stakeholder, 0, len(stakeholders))
+	for _, data := range stakeholders {
+		s, err := stakeholder.FromDataModel(data)
+		if err != nil {
+			return nil, err
+		}
+		res.Stakeholders = append(res.Stakeholders, *s)
+	}
+
+	return res, nil
+}
+
+// ToDataModel converts the stakeholders to IPLD data model without context
+func (s *Stakeholders) ToDataModel() map[string]interface{} {
+	stakeholders := make([]interface{}, 0, len(s.Stakeholders))
+	for i := range s.Stakeholders {
+		stakeholders = append(stakeholders, s.Stakeholders[i].ToDataModel())
+	}
+
+	data := block.NewDataModel(s.Custom)
+	data["stakeholders"] = stakeholders
+	return data
+}
+
+// Encode encodes the stakeholders as an ISCN object
+func (s *Stakeholders) Encode(opts ...block.EncodeOption) (block.IscnObject, error) {
+	return block.Encode(block.CodecStakeholders, s.SchemaVersion, s.ToDataModel(), opts...)
+}
@@ -0,0 +1,86 @@
+package stakeholders_test
+
+import (
+	"testing"
+
+	"github.com/likecoin/iscn-ipld/plugin/block"
+	"github.com/likecoin/iscn-ipld/plugin/block/stakeholder"
+	"github.com/likecoin/iscn-ipld/plugin/block/stakeholders"
+	"github.com/likecoin/iscn-ipld/plugin/internal/blocktest"
+)
+
+func TestStakeholdersRoundTrip(t *testing.T) {
+	stakeholders.Register()
+	stakeholder.Register()
+
+	url := "https://example.com"
+	elems := []stakeholder.Stakeholder{
+		{
+			Type:        "Creator",
+			Stakeholder: blocktest.Link(t, block.CodecEntity, "creator"),
+			Sharing:     80,
+		},
+		{
+			Type:        "FootprintStakeholder",
+			Stakeholder: blocktest.Link(t, block.CodecEntity, "footprint"),
+			Sharing:     10,
+			Footprint:   blocktest.Link(t, block.CodecISCN, "kernel"),
+		},
+		{
+			Type:         "FootprintStakeholder",
+			Stakeholder:  blocktest.Link(t, block.CodecEntity, "url"),
+			Sharing:      10,
+			FootprintURL: &url,
+			Custom:       map[string]interface{}{"extra": "value"},
+		},
+	}
+
+	tests := []struct {
+		name         string
+		stakeholders stakeholders.Stakeholders
+		err          bool
+	}{
+		{"v1", stakeholders.Stakeholders{SchemaVersion: 1, Stakeholders: elems}, false},
+		{"v2", stakeholders.Stakeholders{SchemaVersion: 2, Stakeholders: elems}, false},
+		{"v3", stakeholders.Stakeholders{SchemaVersion: 3, Stakeholders: elems}, false},
+		{"version 0", stakeholders.Stakeholders{Stakeholders: elems}, true},
+		{"footprint of creator", stakeholders.Stakeholders{
+			SchemaVersion: 3,
+			Stakeholders: []stakeholder.Stakeholder{{
+				Type:         "Creator",
+				Stakeholder:  blocktest.Link(t, block.CodecEntity, "creator"),
+				FootprintURL: &url,
+			}},
+		}, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			obj, err := test.stakeholders.Encode()
+			if test.err {
+				if err == nil {
+					t.Fatalf("an error is expected but %s is returned", obj)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			res, err := stakeholders.FromObject(obj)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			again, err := res.Encode()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !again.Cid().Equals(obj.Cid()) {
+				t.Fatalf("CID %s is expected but %s is found", obj.Cid(), again.Cid())
+			}
+		})
+	}
+}
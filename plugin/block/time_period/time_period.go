@@ -13,87 +13,40 @@ const (
 
 // Register registers the schema of time period block
 func Register() {
-	block.RegisterIscnObjectFactory(
+	block.RegisterSchemaSpecs(
 		block.CodecTimePeriod,
 		SchemaName,
-		[]block.CodecFactoryFunc{
-			newSchemaV1,
+		[]block.SchemaSpec{
+			schemaV1,
 		},
 	)
 }
 
-// ==================================================
-// base
-// ==================================================
-
-// base is the base struct for time period (codec 0x033F)
-type base struct {
-	*block.Base
-}
-
-func newBase(version uint64, schema []block.Data) (*base, error) {
-	blockBase, err := block.NewBase(
-		block.CodecTimePeriod,
-		SchemaName,
-		version,
-		schema,
-	)
-	if err != nil {
-		return nil, err
-	}
-
-	return &base{
-		Base: blockBase,
-	}, nil
-}
-
 // ==================================================
 // schemaV1
 // ==================================================
 
-// schemaV1 represents a time period V1
-type schemaV1 struct {
-	*base
-
-	from *block.Timestamp
-	to   *block.Timestamp
-}
-
-var _ block.IscnObject = (*schemaV1)(nil)
-
-func newSchemaV1() (block.Codec, error) {
-	from := block.NewTimestamp("from", false)
-	to := block.NewTimestamp("to", false)
-
-	schema := []block.Data{
-		from,
-		to,
-	}
-
-	timePeriodBase, err := newBase(1, schema)
-	if err != nil {
-		return nil, err
-	}
-
-	obj := schemaV1{
-		base: timePeriodBase,
-		from: from,
-		to:   to,
-	}
-	timePeriodBase.SetValidator(obj.Validate)
-
-	return &obj, nil
+// schemaV1 is the specification of time period V1
+var schemaV1 = block.SchemaSpec{
+	Fields: []block.FieldSpec{
+		{Key: "from", Type: block.FieldTimestamp},
+		{Key: "to", Type: block.FieldTimestamp},
+	},
+	Validate: validate,
 }
 
 // SchemaV1Prototype creates a prototype for schemaV1
 func SchemaV1Prototype() block.Codec {
-	res, _ := newSchemaV1()
+	res, _ := block.NewCodecFactory(block.CodecTimePeriod, SchemaName, 1, schemaV1)()
 	return res
 }
 
-// Validate the data
-func (o *schemaV1) Validate() error {
-	if !o.from.IsDefined() && !o.to.IsDefined() {
+// validate the data
+func validate(b *block.Base) error {
+	from := b.GetHandler("from").(*block.Timestamp)
+	to := b.GetHandler("to").(*block.Timestamp)
+
+	if !from.IsDefined() && !to.IsDefined() {
 		return fmt.Errorf("At least \"from\" or \"to\" exists")
 	}
 
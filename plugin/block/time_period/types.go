@@ -0,0 +1,41 @@
+package timeperiod
+
+import (
+	"github.com/likecoin/iscn-ipld/plugin/block"
+)
+
+// ==================================================
+// TimePeriod
+// ==================================================
+
+// TimePeriod is the typed struct of a time period, at least one of From and To
+// is set
+type TimePeriod struct {
+	From *string
+	To   *string
+
+	Custom map[string]interface{}
+}
+
+// FromDataModel reads a time period in IPLD data model
+func FromDataModel(data map[string]interface{}) (*TimePeriod, error) {
+	f := block.NewFields(data)
+	res := &TimePeriod{
+		From: f.OptionalString("from"),
+		To:   f.OptionalString("to"),
+	}
+	res.Custom = f.Custom()
+
+	if err := f.Err(); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// ToDataModel converts the time period to IPLD data model
+func (p *TimePeriod) ToDataModel() map[string]interface{} {
+	data := block.NewDataModel(p.Custom)
+	block.SetOptionalString(data, "from", p.From)
+	block.SetOptionalString(data, "to", p.To)
+	return data
+}
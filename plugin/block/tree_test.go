@@ -0,0 +1,60 @@
+package block_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/likecoin/iscn-ipld/plugin/block"
+)
+
+func TestTree(t *testing.T) {
+	obj := encode(t, block.CodecRights, 1, map[string]interface{}{
+		"rights": []interface{}{
+			map[string]interface{}{
+				"holder": link(t, block.CodecEntity, "holder"),
+				"type":   "License",
+				"terms":  link(t, block.CodecEntity, "terms"),
+				"period": map[string]interface{}{"from": "2020-01-01T00:00:00Z"},
+			},
+		},
+		"note": map[string]interface{}{"tags": []interface{}{"a", "b"}},
+	})
+
+	tests := []struct {
+		name  string
+		path  string
+		depth int
+		tree  []string
+	}{
+		{"all", "", -1, []string{
+			"context",
+			"rights",
+			"rights/0",
+			"rights/0/holder",
+			"rights/0/type",
+			"rights/0/terms",
+			"rights/0/period",
+			"rights/0/period/from",
+			"note",
+			"note/tags",
+			"note/tags/0",
+			"note/tags/1",
+		}},
+		{"top level", "", 1, []string{"context", "rights", "note"}},
+		{"depth 2", "", 2, []string{"context", "rights", "rights/0", "note", "note/tags"}},
+		{"nested object", "rights/0", 1, []string{"holder", "type", "terms", "period"}},
+		{"nested path", "/rights/0/period/", -1, []string{"from"}},
+		{"custom", "note", -1, []string{"tags", "tags/0", "tags/1"}},
+		{"leaf", "rights/0/type", -1, []string{}},
+		{"not found", "missing", -1, []string{}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			tree := obj.Tree(test.path, test.depth)
+			if !reflect.DeepEqual(tree, test.tree) {
+				t.Fatalf("%v is expected but %v is found", test.tree, tree)
+			}
+		})
+	}
+}
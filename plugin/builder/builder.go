@@ -0,0 +1,214 @@
+package builder
+
+import (
+	"fmt"
+
+	"github.com/ipfs/go-cid"
+	"github.com/likecoin/iscn-ipld/plugin/block"
+	"github.com/likecoin/iscn-ipld/plugin/block/content"
+	"github.com/likecoin/iscn-ipld/plugin/block/entity"
+	"github.com/likecoin/iscn-ipld/plugin/block/kernel"
+	"github.com/likecoin/iscn-ipld/plugin/block/right"
+	"github.com/likecoin/iscn-ipld/plugin/block/rights"
+	"github.com/likecoin/iscn-ipld/plugin/block/stakeholder"
+	"github.com/likecoin/iscn-ipld/plugin/block/stakeholders"
+)
+
+// Record is a complete ISCN record. The links between the objects, i.e. the
+// rights, stakeholders and content of the kernel, the holders of rights and
+// the entities of stakeholders, are set by Build. The schema version of every
+// object should be set
+type Record struct {
+	Kernel       kernel.Kernel
+	Content      content.Content
+	Stakeholders Stakeholders
+	Rights       Rights
+}
+
+// Stakeholders are the stakeholders of an ISCN record
+type Stakeholders struct {
+	SchemaVersion uint64
+
+	Stakeholders []Stakeholder
+
+	Custom map[string]interface{}
+}
+
+// Stakeholder is a stakeholder with its entity. The link to the entity in
+// Stakeholder is kept if Entity is nil, e.g. for an existing entity
+type Stakeholder struct {
+	Entity      *entity.Entity
+	Stakeholder stakeholder.Stakeholder
+}
+
+// Rights are the rights of an ISCN record
+type Rights struct {
+	SchemaVersion uint64
+
+	Rights []Right
+
+	Custom map[string]interface{}
+}
+
+// Right is a right with its holder. The link to the holder in Right is kept if
+// Holder is nil, e.g. for an existing entity
+type Right struct {
+	Holder *entity.Entity
+	Right  right.Right
+}
+
+// Result is the ISCN objects of a record built
+type Result struct {
+	// Kernel is the ISCN kernel of the record
+	Kernel block.IscnObject
+
+	// Blocks are all objects of the record in dependency order, i.e. the
+	// entities, stakeholders, rights, content and kernel, without duplicates
+	Blocks []block.IscnObject
+}
+
+// Build encodes the ISCN objects of the record in dependency order and links
+// them up. Identical entities are encoded once. The options are applied to
+// every object, e.g. block.WithMultihash
+func Build(record *Record, opts ...block.EncodeOption) (*Result, error) {
+	b := &builder{
+		opts:  opts,
+		sizes: map[cid.Cid]uint64{},
+	}
+
+	stakeholdersLink, err := b.stakeholders(&record.Stakeholders)
+	if err != nil {
+		return nil, err
+	}
+
+	rightsLink, err := b.rights(&record.Rights)
+	if err != nil {
+		return nil, err
+	}
+
+	contentObj, err := b.add(record.Content.Encode(b.opts...))
+	if err != nil {
+		return nil, fmt.Errorf("Builder: content: %s", err)
+	}
+
+	k := record.Kernel
+	k.Stakeholders = stakeholdersLink
+	k.Rights = rightsLink
+	k.Content = contentObj.Cid()
+
+	kernelObj, err := b.add(k.Encode(b.opts...))
+	if err != nil {
+		return nil, fmt.Errorf("Builder: kernel: %s", err)
+	}
+
+	return &Result{
+		Kernel: kernelObj,
+		Blocks: b.blocks,
+	}, nil
+}
+
+// ==================================================
+// builder
+// ==================================================
+
+// builder collects the ISCN objects encoded with their cumulative sizes
+type builder struct {
+	opts   []block.EncodeOption
+	sizes  map[cid.Cid]uint64
+	blocks []block.IscnObject
+}
+
+// add collects the ISCN object encoded if it is not collected yet. The sizes
+// of the linked objects collected are set to the object
+func (b *builder) add(obj block.IscnObject, err error) (block.IscnObject, error) {
+	if err != nil {
+		return nil, err
+	}
+
+	for _, link := range obj.Links() {
+		if size, ok := b.sizes[link.Cid]; ok {
+			obj.SetLinkSize(link.Cid, size)
+		}
+	}
+
+	if _, ok := b.sizes[obj.Cid()]; !ok {
+		size, err := obj.Size()
+		if err != nil {
+			return nil, err
+		}
+
+		b.sizes[obj.Cid()] = size
+		b.blocks = append(b.blocks, obj)
+	}
+
+	return obj, nil
+}
+
+// entity encodes the entity and returns its link, or returns 'link' if the
+// entity is nil
+func (b *builder) entity(e *entity.Entity, link cid.Cid) (cid.Cid, error) {
+	if e == nil {
+		return link, nil
+	}
+
+	obj, err := b.add(e.Encode(b.opts...))
+	if err != nil {
+		return cid.Undef, err
+	}
+
+	return obj.Cid(), nil
+}
+
+// stakeholders encodes the entities and the stakeholders
+func (b *builder) stakeholders(s *Stakeholders) (cid.Cid, error) {
+	res := stakeholders.Stakeholders{
+		SchemaVersion: s.SchemaVersion,
+		Stakeholders:  make([]stakeholder.Stakeholder, 0, len(s.Stakeholders)),
+		Custom:        s.Custom,
+	}
+
+	for i, elem := range s.Stakeholders {
+		link, err := b.entity(elem.Entity, elem.Stakeholder.Stakeholder)
+		if err != nil {
+			return cid.Undef, fmt.Errorf("Builder: (Stakeholder %d) entity: %s", i, err)
+		}
+
+		sh := elem.Stakeholder
+		sh.Stakeholder = link
+		res.Stakeholders = append(res.Stakeholders, sh)
+	}
+
+	obj, err := b.add(res.Encode(b.opts...))
+	if err != nil {
+		return cid.Undef, fmt.Errorf("Builder: stakeholders: %s", err)
+	}
+
+	return obj.Cid(), nil
+}
+
+// rights encodes the holders and the rights
+func (b *builder) rights(r *Rights) (cid.Cid, error) {
+	res := rights.Rights{
+		SchemaVersion: r.SchemaVersion,
+		Rights:        make([]right.Right, 0, len(r.Rights)),
+		Custom:        r.Custom,
+	}
+
+	for i, elem := range r.Rights {
+		link, err := b.entity(elem.Holder, elem.Right.Holder)
+		if err != nil {
+			return cid.Undef, fmt.Errorf("Builder: (Right %d) holder: %s", i, err)
+		}
+
+		rt := elem.Right
+		rt.Holder = link
+		res.Rights = append(res.Rights, rt)
+	}
+
+	obj, err := b.add(res.Encode(b.opts...))
+	if err != nil {
+		return cid.Undef, fmt.Errorf("Builder: rights: %s", err)
+	}
+
+	return obj.Cid(), nil
+}
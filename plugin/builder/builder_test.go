@@ -0,0 +1,180 @@
+package builder_test
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/ipfs/go-cid"
+	"github.com/likecoin/iscn-ipld/plugin/block"
+	"github.com/likecoin/iscn-ipld/plugin/block/content"
+	"github.com/likecoin/iscn-ipld/plugin/block/entity"
+	"github.com/likecoin/iscn-ipld/plugin/block/kernel"
+	"github.com/likecoin/iscn-ipld/plugin/block/right"
+	"github.com/likecoin/iscn-ipld/plugin/block/stakeholder"
+	"github.com/likecoin/iscn-ipld/plugin/builder"
+	"github.com/likecoin/iscn-ipld/plugin/internal/blocktest"
+)
+
+func TestMain(m *testing.M) {
+	blocktest.Register()
+	os.Exit(m.Run())
+}
+
+func record(version uint64, existing cid.Cid) *builder.Record {
+	alice := &entity.Entity{SchemaVersion: 1, ID: "llc://alice"}
+	bob := &entity.Entity{SchemaVersion: 1, ID: "llc://bob"}
+
+	return &builder.Record{
+		Kernel: kernel.Kernel{
+			SchemaVersion: version,
+			ID:            make([]byte, 32),
+			Timestamp:     "2020-01-01T00:00:00Z",
+			Version:       1,
+		},
+		Content: content.Content{
+			SchemaVersion: version,
+			Type:          "article",
+			Version:       1,
+			Fingerprint:   "hash://sha256/abc",
+			Title:         "Hello",
+		},
+		Stakeholders: builder.Stakeholders{
+			SchemaVersion: version,
+			Stakeholders: []builder.Stakeholder{
+				{Entity: alice, Stakeholder: stakeholder.Stakeholder{Type: "Creator", Sharing: 60}},
+				{Entity: bob, Stakeholder: stakeholder.Stakeholder{Type: "Editor", Sharing: 40}},
+			},
+		},
+		Rights: builder.Rights{
+			SchemaVersion: 1,
+			Rights: []builder.Right{
+				// The same entity as the creator
+				{Holder: &entity.Entity{SchemaVersion: 1, ID: "llc://alice"},
+					Right: right.Right{Type: "License", Terms: existing}},
+				// An existing entity
+				{Right: right.Right{Holder: existing, Type: "License", Terms: existing}},
+			},
+		},
+	}
+}
+
+func TestBuild(t *testing.T) {
+	existing, err := block.Encode(block.CodecEntity, 1, map[string]interface{}{"id": "llc://existing"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		version uint64
+	}{
+		{"v1", 1},
+		{"v3", 3},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			res, err := builder.Build(record(test.version, existing.Cid()))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			// alice, bob, stakeholders, rights, content and kernel
+			names := []string{}
+			objects := map[cid.Cid]block.IscnObject{}
+			for _, obj := range res.Blocks {
+				names = append(names, obj.GetName())
+				objects[obj.Cid()] = obj
+			}
+
+			expected := "entity,entity,stakeholders,rights,content,iscn"
+			if strings.Join(names, ",") != expected {
+				t.Fatalf("%s is expected but %s is found", expected, strings.Join(names, ","))
+			}
+
+			if res.Blocks[len(res.Blocks)-1] != res.Kernel {
+				t.Fatal("the kernel is expected as the last block")
+			}
+
+			// Every link to a block built is resolved and carries its cumulative size
+			for _, obj := range res.Blocks {
+				size := uint64(len(obj.RawData()))
+				for _, l := range obj.Links() {
+					linked, ok := objects[l.Cid]
+					if !ok {
+						if !l.Cid.Equals(existing.Cid()) {
+							t.Fatalf("%s of %s is not built", l.Name, obj)
+						}
+						continue
+					}
+
+					linkedSize, err := linked.Size()
+					if err != nil {
+						t.Fatal(err)
+					}
+
+					if l.Size != linkedSize {
+						t.Fatalf("size %d is expected for %s of %s but %d is found",
+							linkedSize, l.Name, obj, l.Size)
+					}
+					size += l.Size
+				}
+
+				objSize, err := obj.Size()
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				if objSize != size {
+					t.Fatalf("size %d is expected for %s but %d is found", size, obj, objSize)
+				}
+			}
+		})
+	}
+}
+
+func TestBuildError(t *testing.T) {
+	existing, err := block.Encode(block.CodecEntity, 1, map[string]interface{}{"id": "llc://existing"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name   string
+		modify func(*builder.Record)
+		err    string
+	}{
+		{"stakeholder entity", func(r *builder.Record) {
+			r.Stakeholders.Stakeholders[0].Entity.SchemaVersion = 0
+		}, "Builder: (Stakeholder 0) entity:"},
+		{"right holder", func(r *builder.Record) {
+			r.Rights.Rights[0].Holder.SchemaVersion = 9
+		}, "Builder: (Right 0) holder:"},
+		{"stakeholders", func(r *builder.Record) {
+			r.Stakeholders.Stakeholders[0].Stakeholder.Type = "Unknown"
+		}, "Builder: stakeholders:"},
+		{"content", func(r *builder.Record) {
+			r.Content.SchemaVersion = 0
+		}, "Builder: content:"},
+		{"kernel", func(r *builder.Record) {
+			r.Kernel.SchemaVersion = 0
+		}, "Builder: kernel:"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			r := record(1, existing.Cid())
+			test.modify(r)
+
+			res, err := builder.Build(r)
+			if err == nil {
+				t.Fatalf("an error is expected but %s is returned", res.Kernel)
+			}
+
+			if !strings.HasPrefix(err.Error(), test.err) {
+				t.Fatalf("%q is expected but %q is found", test.err, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,468 @@
+package car
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math"
+
+	"github.com/ipfs/go-cid"
+	"github.com/likecoin/iscn-ipld/plugin/block"
+
+	blocks "github.com/ipfs/go-block-format"
+	cbor "github.com/ipfs/go-ipld-cbor"
+)
+
+// The CAR (Content Addressable aRchive) formats
+// See the authoritative documents:
+// https://ipld.io/specs/transport/car/carv1/
+// https://ipld.io/specs/transport/car/carv2/
+
+// maxSectionSize is the maximum size of a section, i.e. a CID with its data
+const maxSectionSize = 8 << 20
+
+// carV2Pragma is the CARv1 header of {"version": 2} which starts a CARv2 file
+var carV2Pragma = []byte{0x0a, 0xa1, 0x67, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x02}
+
+// carV2HeaderSize is the size of the CARv2 header after the pragma
+const carV2HeaderSize = 40
+
+// BlockGetter is the interface to retrieve blocks, e.g. a blockstore
+type BlockGetter interface {
+	Get(cid.Cid) (blocks.Block, error)
+}
+
+// BlockPutter is the interface to store blocks, e.g. a blockstore
+type BlockPutter interface {
+	Put(blocks.Block) error
+}
+
+// ==================================================
+// Writer
+// ==================================================
+
+// Option is an option for walking through an ISCN record
+type Option func(*walker)
+
+// WithDepth sets how many levels of ISCN kernels linked as parent or footprint
+// are included, all are included if 'depth' is -1. Only the record of the root
+// kernel is included by default
+func WithDepth(depth int) Option {
+	return func(w *walker) {
+		w.depth = depth
+	}
+}
+
+// WithExternalLinks includes the blocks linked by the ISCN objects which are
+// not ISCN objects, e.g. the terms of a right. The linked blocks are included
+// as is and their links are not followed
+func WithExternalLinks() Option {
+	return func(w *walker) {
+		w.isExternal = true
+	}
+}
+
+// Collect walks through the ISCN record of the kernel 'root' and returns the
+// blocks in depth-first order starting from the root kernel
+func Collect(getter BlockGetter, root cid.Cid, opts ...Option) ([]blocks.Block, error) {
+	if root.Type() != block.CodecISCN {
+		return nil, fmt.Errorf("Cid %q is not an ISCN kernel", root.String())
+	}
+
+	w := &walker{
+		getter:  getter,
+		visited: map[cid.Cid]struct{}{},
+	}
+	for _, opt := range opts {
+		opt(w)
+	}
+
+	if err := w.walk(root, 0); err != nil {
+		return nil, err
+	}
+
+	return w.blocks, nil
+}
+
+// WriteV1 writes the ISCN record of the kernel 'root' as a CARv1 file
+func WriteV1(out io.Writer, getter BlockGetter, root cid.Cid, opts ...Option) error {
+	blks, err := Collect(getter, root, opts...)
+	if err != nil {
+		return err
+	}
+
+	return writeV1(out, []cid.Cid{root}, blks)
+}
+
+// WriteV2 writes the ISCN record of the kernel 'root' as a CARv2 file without
+// index
+func WriteV2(out io.Writer, getter BlockGetter, root cid.Cid, opts ...Option) error {
+	blks, err := Collect(getter, root, opts...)
+	if err != nil {
+		return err
+	}
+
+	payload := &bytes.Buffer{}
+	if err := writeV1(payload, []cid.Cid{root}, blks); err != nil {
+		return err
+	}
+
+	// Characteristics (16 bytes), data offset, data size and index offset
+	header := make([]byte, carV2HeaderSize)
+	binary.LittleEndian.PutUint64(header[16:], uint64(len(carV2Pragma)+carV2HeaderSize))
+	binary.LittleEndian.PutUint64(header[24:], uint64(payload.Len()))
+
+	for _, b := range [][]byte{carV2Pragma, header, payload.Bytes()} {
+		if _, err := out.Write(b); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// writeV1 writes the roots and blocks as a CARv1 file
+func writeV1(out io.Writer, roots []cid.Cid, blks []blocks.Block) error {
+	header, err := cbor.DumpObject(map[string]interface{}{
+		"roots":   roots,
+		"version": 1,
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := writeSection(out, header); err != nil {
+		return err
+	}
+
+	for _, blk := range blks {
+		if err := writeSection(out, blk.Cid().Bytes(), blk.RawData()); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// writeSection writes the data prefixed by the total length as varint
+func writeSection(out io.Writer, data ...[]byte) error {
+	length := 0
+	for _, d := range data {
+		length += len(d)
+	}
+
+	buffer := make([]byte, binary.MaxVarintLen64)
+	n := binary.PutUvarint(buffer, uint64(length))
+	if _, err := out.Write(buffer[:n]); err != nil {
+		return err
+	}
+
+	for _, d := range data {
+		if _, err := out.Write(d); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ==================================================
+// walker
+// ==================================================
+
+// walker walks through the links of ISCN objects
+type walker struct {
+	getter     BlockGetter
+	depth      int
+	isExternal bool
+
+	visited map[cid.Cid]struct{}
+	blocks  []blocks.Block
+}
+
+// walk collects the block 'c' and the blocks linked by it, 'depth' is the
+// level of the ISCN kernel which the block belongs to
+func (w *walker) walk(c cid.Cid, depth int) error {
+	if _, ok := w.visited[c]; ok {
+		return nil
+	}
+
+	codec := c.Type()
+	if !block.IsIscnObject(codec) {
+		if !w.isExternal {
+			return nil
+		}
+
+		blk, err := w.getter.Get(c)
+		if err != nil {
+			return err
+		}
+
+		w.visited[c] = struct{}{}
+		w.blocks = append(w.blocks, blk)
+		return nil
+	}
+
+	blk, err := w.getter.Get(c)
+	if err != nil {
+		return err
+	}
+
+	obj, err := block.Decode(blk.RawData(), c)
+	if err != nil {
+		return err
+	}
+
+	w.visited[c] = struct{}{}
+	w.blocks = append(w.blocks, blk)
+
+	for _, link := range obj.Links() {
+		// The parent and footprint kernels are in the next level
+		next := depth
+		if link.Cid.Type() == block.CodecISCN {
+			next++
+			if w.depth >= 0 && next > w.depth {
+				continue
+			}
+		}
+
+		if err := w.walk(link.Cid, next); err != nil {
+			return fmt.Errorf("%s: %s", link.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// ==================================================
+// Reader
+// ==================================================
+
+// Read reads a CARv1 or CARv2 file and verifies every block, ISCN objects are
+// verified by block.Decode and the hash of other blocks is checked. No block
+// is stored unless all of them are verified, and the roots are returned
+func Read(in io.Reader, putter BlockPutter) ([]cid.Cid, error) {
+	roots, blks, err := readAll(bufio.NewReader(in))
+	if err != nil {
+		return nil, err
+	}
+
+	found := map[cid.Cid]struct{}{}
+	for _, blk := range blks {
+		if err := verify(blk); err != nil {
+			return nil, err
+		}
+		found[blk.Cid()] = struct{}{}
+	}
+
+	for _, root := range roots {
+		if _, ok := found[root]; !ok {
+			return nil, fmt.Errorf("Cid %q: the root is not found", root.String())
+		}
+	}
+
+	for _, blk := range blks {
+		if err := putter.Put(blk); err != nil {
+			return nil, err
+		}
+	}
+
+	return roots, nil
+}
+
+// verify verifies the data of the block against its CID
+func verify(blk blocks.Block) error {
+	c := blk.Cid()
+	if block.IsIscnObject(c.Type()) {
+		if _, err := block.Decode(blk.RawData(), c); err != nil {
+			return fmt.Errorf("Cid %q: %s", c.String(), err)
+		}
+		return nil
+	}
+
+	expected, err := c.Prefix().Sum(blk.RawData())
+	if err != nil {
+		return err
+	}
+
+	if !expected.Equals(c) {
+		return fmt.Errorf("Cid %q: the hash of data is not matched", c.String())
+	}
+
+	return nil
+}
+
+// readAll reads the roots and blocks from a CARv1 or CARv2 file
+func readAll(r *bufio.Reader) ([]cid.Cid, []blocks.Block, error) {
+	version, roots, err := readHeader(r)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	switch version {
+	case 1:
+		blks, err := readBlocks(r)
+		return roots, blks, err
+	case 2:
+		payload, err := readV2Payload(r)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		return readV1(bufio.NewReader(payload))
+	}
+
+	return nil, nil, fmt.Errorf("CAR: version %d is not supported", version)
+}
+
+// readV1 reads the roots and blocks from a CARv1 file, e.g. the payload of a
+// CARv2 file, which cannot be another CARv2 file
+func readV1(r *bufio.Reader) ([]cid.Cid, []blocks.Block, error) {
+	version, roots, err := readHeader(r)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if version != 1 {
+		return nil, nil, fmt.Errorf("CAR: CARv1 is expected but version %d is found", version)
+	}
+
+	blks, err := readBlocks(r)
+	return roots, blks, err
+}
+
+// readV2Payload reads the CARv1 payload of a CARv2 file after the pragma, the
+// payload should be within the file
+func readV2Payload(r io.Reader) (io.Reader, error) {
+	header := make([]byte, carV2HeaderSize)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return nil, fmt.Errorf("CAR: truncated CARv2 header: %s", err)
+	}
+
+	dataOffset := binary.LittleEndian.Uint64(header[16:])
+	dataSize := binary.LittleEndian.Uint64(header[24:])
+
+	read := uint64(len(carV2Pragma) + carV2HeaderSize)
+	if dataOffset < read || dataOffset-read > math.MaxInt64 {
+		return nil, fmt.Errorf("CAR: invalid data offset %d", dataOffset)
+	}
+
+	if dataSize > math.MaxInt64 {
+		return nil, fmt.Errorf("CAR: invalid data size %d", dataSize)
+	}
+
+	if _, err := io.CopyN(ioutil.Discard, r, int64(dataOffset-read)); err != nil {
+		return nil, fmt.Errorf("CAR: data offset %d is outside the file", dataOffset)
+	}
+
+	payload := &bytes.Buffer{}
+	if _, err := io.CopyN(payload, r, int64(dataSize)); err != nil {
+		return nil, fmt.Errorf("CAR: data size %d is outside the file", dataSize)
+	}
+
+	return payload, nil
+}
+
+// readHeader reads the CARv1 header, or the pragma of a CARv2 file
+func readHeader(r *bufio.Reader) (uint64, []cid.Cid, error) {
+	header, err := readSection(r)
+	if err == io.EOF {
+		return 0, nil, fmt.Errorf("CAR: header is not found")
+	}
+	if err != nil {
+		return 0, nil, err
+	}
+
+	return parseHeader(header)
+}
+
+// parseHeader parses the version and roots from the CARv1 header
+func parseHeader(header []byte) (uint64, []cid.Cid, error) {
+	m := map[string]interface{}{}
+	if err := cbor.DecodeInto(header, &m); err != nil {
+		return 0, nil, err
+	}
+
+	var version uint64
+	switch v := m["version"].(type) {
+	case int:
+		version = uint64(v)
+	case int64:
+		version = uint64(v)
+	case uint64:
+		version = v
+	default:
+		return 0, nil, fmt.Errorf("CAR: version is not found in header")
+	}
+
+	if version == 2 {
+		return version, nil, nil
+	}
+
+	values, ok := m["roots"].([]interface{})
+	if !ok || len(values) == 0 {
+		return 0, nil, fmt.Errorf("CAR: roots are not found in header")
+	}
+
+	roots := make([]cid.Cid, 0, len(values))
+	for _, value := range values {
+		c, ok := value.(cid.Cid)
+		if !ok {
+			return 0, nil, fmt.Errorf("CAR: 'cid.Cid' is expected in roots but '%T' is found", value)
+		}
+		roots = append(roots, c)
+	}
+
+	return version, roots, nil
+}
+
+// readBlocks reads the blocks until the end of a CARv1 payload
+func readBlocks(r *bufio.Reader) ([]blocks.Block, error) {
+	blks := []blocks.Block{}
+	for {
+		section, err := readSection(r)
+		if err == io.EOF {
+			return blks, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		n, c, err := cid.CidFromBytes(section)
+		if err != nil {
+			return nil, err
+		}
+
+		blk, err := blocks.NewBlockWithCid(section[n:], c)
+		if err != nil {
+			return nil, err
+		}
+		blks = append(blks, blk)
+	}
+}
+
+// readSection reads the data prefixed by the length as varint, io.EOF is
+// returned only if there is no more section
+func readSection(r *bufio.Reader) ([]byte, error) {
+	length, err := binary.ReadUvarint(r)
+	if err != nil {
+		if err == io.EOF {
+			return nil, io.EOF
+		}
+		return nil, fmt.Errorf("CAR: invalid section length: %s", err)
+	}
+
+	if length == 0 || length > maxSectionSize {
+		return nil, fmt.Errorf("CAR: invalid section length %d", length)
+	}
+
+	section := make([]byte, length)
+	if _, err := io.ReadFull(r, section); err != nil {
+		return nil, fmt.Errorf("CAR: truncated section: %s", err)
+	}
+
+	return section, nil
+}
@@ -0,0 +1,215 @@
+package car_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/ipfs/go-cid"
+	"github.com/likecoin/iscn-ipld/plugin/block"
+	"github.com/likecoin/iscn-ipld/plugin/car"
+	"github.com/likecoin/iscn-ipld/plugin/internal/blocktest"
+
+	blocks "github.com/ipfs/go-block-format"
+)
+
+// store is a blockstore in memory
+type store map[cid.Cid]blocks.Block
+
+func (s store) Get(c cid.Cid) (blocks.Block, error) {
+	blk, ok := s[c]
+	if !ok {
+		return nil, fmt.Errorf("Cid %q is not found", c.String())
+	}
+	return blk, nil
+}
+
+func (s store) Put(blk blocks.Block) error {
+	s[blk.Cid()] = blk
+	return nil
+}
+
+func TestMain(m *testing.M) {
+	blocktest.Register()
+	os.Exit(m.Run())
+}
+
+// record creates an ISCN record in a store and returns the kernel
+func record(t *testing.T) (store, cid.Cid) {
+	t.Helper()
+
+	s := store{}
+	put := func(codec uint64, version uint64, data map[string]interface{}) cid.Cid {
+		obj, err := block.Encode(codec, version, data)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		blk, err := blocks.NewBlockWithCid(obj.RawData(), obj.Cid())
+		if err != nil {
+			t.Fatal(err)
+		}
+		s.Put(blk)
+		return obj.Cid()
+	}
+
+	holder := put(block.CodecEntity, 1, map[string]interface{}{"id": "llc://holder"})
+	terms := blocks.NewBlock([]byte("terms"))
+	s.Put(terms)
+
+	return s, put(block.CodecISCN, 2, map[string]interface{}{
+		"id":        make([]byte, 32),
+		"timestamp": "2020-01-01T00:00:00Z",
+		"version":   1,
+		"rights": put(block.CodecRights, 2, map[string]interface{}{
+			"rights": []interface{}{
+				map[string]interface{}{"holder": holder, "type": "License", "terms": terms.Cid()},
+			},
+		}),
+		"stakeholders": put(block.CodecStakeholders, 2, map[string]interface{}{
+			"stakeholders": []interface{}{
+				map[string]interface{}{"type": "Creator", "stakeholder": holder, "sharing": 100},
+			},
+		}),
+		"content": put(block.CodecContent, 2, map[string]interface{}{
+			"type":        "article",
+			"version":     1,
+			"fingerprint": "hash://sha256/abc",
+			"title":       "Title",
+		}),
+	})
+}
+
+func TestWriteAndRead(t *testing.T) {
+	s, root := record(t)
+
+	tests := []struct {
+		name   string
+		write  func(*bytes.Buffer, car.BlockGetter, cid.Cid, ...car.Option) error
+		opts   []car.Option
+		blocks int
+	}{
+		{"CARv1", func(b *bytes.Buffer, g car.BlockGetter, c cid.Cid, opts ...car.Option) error {
+			return car.WriteV1(b, g, c, opts...)
+		}, nil, 5},
+		{"CARv2", func(b *bytes.Buffer, g car.BlockGetter, c cid.Cid, opts ...car.Option) error {
+			return car.WriteV2(b, g, c, opts...)
+		}, nil, 5},
+		{"external links", func(b *bytes.Buffer, g car.BlockGetter, c cid.Cid, opts ...car.Option) error {
+			return car.WriteV2(b, g, c, opts...)
+		}, []car.Option{car.WithExternalLinks()}, 6},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			buf := &bytes.Buffer{}
+			if err := test.write(buf, s, root, test.opts...); err != nil {
+				t.Fatal(err)
+			}
+
+			res := store{}
+			roots, err := car.Read(buf, res)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if len(roots) != 1 || !roots[0].Equals(root) {
+				t.Fatalf("root %s is expected but %v is found", root, roots)
+			}
+
+			if len(res) != test.blocks {
+				t.Fatalf("%d blocks are expected but %d are found", test.blocks, len(res))
+			}
+
+			for c, blk := range res {
+				if !bytes.Equal(blk.RawData(), s[c].RawData()) {
+					t.Fatalf("Cid %q: the data is not matched", c.String())
+				}
+			}
+		})
+	}
+}
+
+func TestReadInvalid(t *testing.T) {
+	s, root := record(t)
+
+	v1 := &bytes.Buffer{}
+	if err := car.WriteV1(v1, s, root); err != nil {
+		t.Fatal(err)
+	}
+
+	v2 := &bytes.Buffer{}
+	if err := car.WriteV2(v2, s, root); err != nil {
+		t.Fatal(err)
+	}
+
+	// The CARv2 pragma and header are 11 and 40 bytes
+	pragma := v2.Bytes()[:11]
+	v2Header := func(offset uint64, size uint64) []byte {
+		header := make([]byte, 40)
+		binary.LittleEndian.PutUint64(header[16:], offset)
+		binary.LittleEndian.PutUint64(header[24:], size)
+		return append(append([]byte{}, pragma...), header...)
+	}
+	concat := func(data ...[]byte) []byte {
+		return bytes.Join(data, nil)
+	}
+
+	corrupted := append([]byte{}, v1.Bytes()...)
+	corrupted[len(corrupted)-1] ^= 0xff
+
+	tests := []struct {
+		name string
+		data []byte
+		err  string
+	}{
+		{"empty", nil, "header is not found"},
+		{"truncated", v1.Bytes()[:v1.Len()-1], "truncated section"},
+		{"corrupted", corrupted, "Cid"},
+		{"nested CARv2", concat(v2Header(51, 51+uint64(v1.Len())), v2Header(51, uint64(v1.Len())), v1.Bytes()),
+			"CARv1 is expected but version 2 is found"},
+		{"data offset before header", concat(v2Header(10, uint64(v1.Len())), v1.Bytes()), "invalid data offset"},
+		{"data offset outside", concat(v2Header(1000000, uint64(v1.Len())), v1.Bytes()), "outside the file"},
+		{"data size outside", concat(v2Header(51, uint64(v1.Len())+1), v1.Bytes()), "outside the file"},
+		{"data size too large", concat(v2Header(51, 1<<63), v1.Bytes()), "invalid data size"},
+		{"truncated CARv2 header", pragma, "truncated CARv2 header"},
+		{"version 3", concat([]byte{0x0a, 0xa1, 0x67, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x03}),
+			"roots are not found"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			res := store{}
+			_, err := car.Read(bytes.NewReader(test.data), res)
+			if err == nil {
+				t.Fatal("an error is expected")
+			}
+
+			if !strings.Contains(err.Error(), test.err) {
+				t.Fatalf("%q is expected in the error but %q is found", test.err, err)
+			}
+
+			if len(res) != 0 {
+				t.Fatalf("no block is expected to be stored but %d are found", len(res))
+			}
+		})
+	}
+}
+
+func TestReadSmallerDataSize(t *testing.T) {
+	s, root := record(t)
+
+	v2 := &bytes.Buffer{}
+	if err := car.WriteV2(v2, s, root); err != nil {
+		t.Fatal(err)
+	}
+
+	// The payload ends at the data size even if more data follows
+	data := append(v2.Bytes(), 0xff, 0xff)
+	if _, err := car.Read(bytes.NewReader(data), store{}); err != nil {
+		t.Fatal(err)
+	}
+}
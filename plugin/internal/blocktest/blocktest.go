@@ -0,0 +1,58 @@
+// Package blocktest provides the helpers for testing ISCN objects
+package blocktest
+
+import (
+	"testing"
+
+	"github.com/ipfs/go-cid"
+	"github.com/likecoin/iscn-ipld/plugin/block"
+	"github.com/likecoin/iscn-ipld/plugin/block/content"
+	"github.com/likecoin/iscn-ipld/plugin/block/entity"
+	"github.com/likecoin/iscn-ipld/plugin/block/kernel"
+	"github.com/likecoin/iscn-ipld/plugin/block/right"
+	"github.com/likecoin/iscn-ipld/plugin/block/rights"
+	"github.com/likecoin/iscn-ipld/plugin/block/stakeholder"
+	"github.com/likecoin/iscn-ipld/plugin/block/stakeholders"
+	timeperiod "github.com/likecoin/iscn-ipld/plugin/block/time_period"
+	"github.com/multiformats/go-multihash"
+)
+
+// Register registers the schemas of all ISCN objects
+func Register() {
+	kernel.Register()
+	rights.Register()
+	stakeholders.Register()
+	content.Register()
+	entity.Register()
+
+	right.Register()
+	stakeholder.Register()
+	timeperiod.Register()
+}
+
+// Link returns a CID of 'codec' with the SHA2-256 hash of 'data'
+func Link(t testing.TB, codec uint64, data string) cid.Cid {
+	t.Helper()
+
+	c, err := cid.Prefix{
+		Version:  1,
+		Codec:    codec,
+		MhType:   multihash.SHA2_256,
+		MhLength: -1,
+	}.Sum([]byte(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
+
+// Encode encodes the data as the ISCN object of schema 'version' of 'codec'
+func Encode(t testing.TB, codec uint64, version uint64, data map[string]interface{}) block.IscnObject {
+	t.Helper()
+
+	obj, err := block.Encode(codec, version, data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return obj
+}
@@ -2,6 +2,8 @@ package plugin
 
 import (
 	"fmt"
+	"io"
+	"io/ioutil"
 
 	"github.com/ipfs/go-ipfs/core/coredag"
 	"github.com/ipfs/go-ipfs/plugin"
@@ -70,5 +72,46 @@ func (*Plugin) RegisterBlockDecoders(decoder ipld.BlockDecoder) error {
 
 // RegisterInputEncParsers registers the encode parsers needed to put the blocks into the DAG
 func (*Plugin) RegisterInputEncParsers(encodingParsers coredag.InputEncParsers) error {
+	encodingParsers.AddParser("json", kernel.SchemaName, jsonParser(block.CodecISCN))
+	encodingParsers.AddParser("json", rights.SchemaName, jsonParser(block.CodecRights))
+	encodingParsers.AddParser("json", stakeholders.SchemaName, jsonParser(block.CodecStakeholders))
+	encodingParsers.AddParser("json", content.SchemaName, jsonParser(block.CodecContent))
+	encodingParsers.AddParser("json", entity.SchemaName, jsonParser(block.CodecEntity))
+
+	encodingParsers.AddParser("cbor", kernel.SchemaName, cborParser(block.CodecISCN))
+	encodingParsers.AddParser("cbor", rights.SchemaName, cborParser(block.CodecRights))
+	encodingParsers.AddParser("cbor", stakeholders.SchemaName, cborParser(block.CodecStakeholders))
+	encodingParsers.AddParser("cbor", content.SchemaName, cborParser(block.CodecContent))
+	encodingParsers.AddParser("cbor", entity.SchemaName, cborParser(block.CodecEntity))
 	return nil
 }
+
+// jsonParser returns a parser which parses the JSON input as the ISCN object of codec
+func jsonParser(codec uint64) coredag.DagParser {
+	return func(r io.Reader, mhType uint64, mhLen int) ([]ipld.Node, error) {
+		obj, err := block.ParseJSON(codec, r, block.WithMultihash(mhType, mhLen))
+		if err != nil {
+			return nil, err
+		}
+
+		return []ipld.Node{obj}, nil
+	}
+}
+
+// cborParser returns a parser which validates the serialized CBOR input against
+// the schema of codec
+func cborParser(codec uint64) coredag.DagParser {
+	return func(r io.Reader, mhType uint64, mhLen int) ([]ipld.Node, error) {
+		rawData, err := ioutil.ReadAll(r)
+		if err != nil {
+			return nil, err
+		}
+
+		obj, _, err := block.DecodeWithCodec(rawData, codec, block.WithMultihash(mhType, mhLen))
+		if err != nil {
+			return nil, err
+		}
+
+		return []ipld.Node{obj}, nil
+	}
+}
@@ -0,0 +1,157 @@
+package prime
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+
+	"github.com/ipld/go-ipld-prime/codec"
+	"github.com/ipld/go-ipld-prime/datamodel"
+	"github.com/ipld/go-ipld-prime/linking/cid"
+	"github.com/ipld/go-ipld-prime/multicodec"
+	"github.com/likecoin/iscn-ipld/plugin/block"
+)
+
+// Register registers the encoders and decoders of ISCN objects into the
+// multicodec registry of ipld-prime. The schemas of ISCN objects should be
+// registered before
+func Register() {
+	for _, c := range []uint64{
+		block.CodecISCN,
+		block.CodecRights,
+		block.CodecStakeholders,
+		block.CodecContent,
+		block.CodecEntity,
+	} {
+		multicodec.RegisterEncoder(c, Encoder(c))
+		multicodec.RegisterDecoder(c, Decoder(c))
+	}
+}
+
+// Encoder returns an encoder which encodes a node as the ISCN object of 'codec'
+func Encoder(c uint64) codec.Encoder {
+	return func(n datamodel.Node, w io.Writer) error {
+		obj, err := ToObject(c, n)
+		if err != nil {
+			return err
+		}
+
+		_, err = w.Write(obj.RawData())
+		return err
+	}
+}
+
+// Decoder returns a decoder which decodes the serialized ISCN object of 'codec'
+func Decoder(c uint64) codec.Decoder {
+	return func(na datamodel.NodeAssembler, r io.Reader) error {
+		rawData, err := ioutil.ReadAll(r)
+		if err != nil {
+			return err
+		}
+
+		obj, _, err := block.DecodeWithCodec(rawData, c)
+		if err != nil {
+			return err
+		}
+
+		n, err := NewNode(obj)
+		if err != nil {
+			return err
+		}
+
+		return datamodel.Copy(n, na)
+	}
+}
+
+// ToObject converts the node to the ISCN object of 'codec'
+func ToObject(c uint64, n datamodel.Node) (block.IscnObject, error) {
+	if on, ok := n.(*objectNode); ok && on.obj.Cid().Type() == c {
+		return on.obj, nil
+	}
+
+	if n.Kind() != datamodel.Kind_Map {
+		return nil, fmt.Errorf("Prime: map is expected but %s is found", n.Kind())
+	}
+
+	value, err := toValue(n)
+	if err != nil {
+		return nil, err
+	}
+
+	data := value.(map[string]interface{})
+	version, err := block.ParseContext(c, data[block.ContextKey])
+	if err != nil {
+		return nil, err
+	}
+	delete(data, block.ContextKey)
+
+	return block.Encode(c, version, data)
+}
+
+// toValue converts the node to the value accepted by the data handlers
+func toValue(n datamodel.Node) (interface{}, error) {
+	switch n.Kind() {
+	case datamodel.Kind_Null:
+		return nil, nil
+	case datamodel.Kind_Bool:
+		return n.AsBool()
+	case datamodel.Kind_Int:
+		return n.AsInt()
+	case datamodel.Kind_Float:
+		return n.AsFloat()
+	case datamodel.Kind_String:
+		return n.AsString()
+	case datamodel.Kind_Bytes:
+		return n.AsBytes()
+	case datamodel.Kind_Link:
+		link, err := n.AsLink()
+		if err != nil {
+			return nil, err
+		}
+
+		cl, ok := link.(cidlink.Link)
+		if !ok {
+			return nil, fmt.Errorf("Prime: Cid link is expected but '%T' is found", link)
+		}
+		return cl.Cid, nil
+	case datamodel.Kind_Map:
+		res := map[string]interface{}{}
+		it := n.MapIterator()
+		for !it.Done() {
+			k, v, err := it.Next()
+			if err != nil {
+				return nil, err
+			}
+
+			key, err := k.AsString()
+			if err != nil {
+				return nil, err
+			}
+
+			value, err := toValue(v)
+			if err != nil {
+				return nil, err
+			}
+			res[key] = value
+		}
+		return res, nil
+	case datamodel.Kind_List:
+		res := []interface{}{}
+		it := n.ListIterator()
+		for !it.Done() {
+			_, v, err := it.Next()
+			if err != nil {
+				return nil, err
+			}
+
+			value, err := toValue(v)
+			if err != nil {
+				return nil, err
+			}
+			res = append(res, value)
+		}
+		return res, nil
+	}
+
+	return nil, fmt.Errorf("Prime: %s is not supported", n.Kind())
+}
@@ -0,0 +1,208 @@
+package prime_test
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"github.com/ipfs/go-cid"
+	"github.com/ipld/go-ipld-prime/datamodel"
+	"github.com/ipld/go-ipld-prime/linking/cid"
+	"github.com/ipld/go-ipld-prime/multicodec"
+	"github.com/ipld/go-ipld-prime/node/basicnode"
+	"github.com/likecoin/iscn-ipld/plugin/block"
+	"github.com/likecoin/iscn-ipld/plugin/block/content"
+	"github.com/likecoin/iscn-ipld/plugin/block/entity"
+	"github.com/likecoin/iscn-ipld/plugin/block/kernel"
+	"github.com/likecoin/iscn-ipld/plugin/prime"
+)
+
+func TestCodec(t *testing.T) {
+	kernel.Register()
+	content.Register()
+	entity.Register()
+	prime.Register()
+
+	e, err := block.Encode(block.CodecEntity, 1, map[string]interface{}{
+		"id":   "llc://alice",
+		"name": "Alice",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	parent, err := block.Encode(block.CodecContent, 3, map[string]interface{}{
+		"type":        "article",
+		"version":     1,
+		"fingerprint": "hash://sha256/abc",
+		"title":       "Hello",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		codec   uint64
+		version uint64
+		data    map[string]interface{}
+	}{
+		{"entity", block.CodecEntity, 1, map[string]interface{}{"id": "llc://bob"}},
+		{"content v1", block.CodecContent, 1, map[string]interface{}{
+			"type":        "article",
+			"version":     1,
+			"fingerprint": "hash://sha256/abc",
+			"title":       "Hello",
+			"tags":        []interface{}{"a", "b"},
+			"extra":       map[string]interface{}{"link": e.Cid(), "bytes": []byte{1, 2}},
+		}},
+		{"content v3", block.CodecContent, 3, map[string]interface{}{
+			"type":        "article",
+			"version":     2,
+			"parent":      parent.Cid(),
+			"fingerprint": "hash://sha256/abc",
+			"title":       "Hello",
+		}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			obj, err := block.Encode(test.codec, test.version, test.data)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			decoder, err := multicodec.LookupDecoder(test.codec)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			nb := basicnode.Prototype.Any.NewBuilder()
+			if err := decoder(nb, bytes.NewReader(obj.RawData())); err != nil {
+				t.Fatal(err)
+			}
+			n := nb.Build()
+
+			if n.Kind() != datamodel.Kind_Map {
+				t.Fatalf("map is expected but %s is found", n.Kind())
+			}
+
+			encoder, err := multicodec.LookupEncoder(test.codec)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var buf bytes.Buffer
+			if err := encoder(n, &buf); err != nil {
+				t.Fatal(err)
+			}
+
+			if !bytes.Equal(buf.Bytes(), obj.RawData()) {
+				t.Fatalf("the raw data is changed after a round trip")
+			}
+		})
+	}
+}
+
+func TestNodeKinds(t *testing.T) {
+	kernel.Register()
+	content.Register()
+	entity.Register()
+
+	e, err := block.Encode(block.CodecEntity, 1, map[string]interface{}{"id": "llc://alice"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c, err := block.Encode(block.CodecContent, 2, map[string]interface{}{
+		"type":        "article",
+		"version":     1,
+		"fingerprint": "hash://sha256/abc",
+		"title":       "Hello",
+		"tags":        []interface{}{"a"},
+		"holder":      e.Cid(),
+		"raw":         []byte{1},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	k, err := block.Encode(block.CodecISCN, 1, map[string]interface{}{
+		"id":           make([]byte, 32),
+		"timestamp":    "2020-01-01T00:00:00Z",
+		"version":      1,
+		"rights":       cid.NewCidV1(block.CodecRights, e.Cid().Hash()),
+		"stakeholders": cid.NewCidV1(block.CodecStakeholders, e.Cid().Hash()),
+		"content":      c.Cid(),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name  string
+		obj   block.IscnObject
+		key   string
+		kind  datamodel.Kind
+		value interface{}
+	}{
+		{"context", c, "context", datamodel.Kind_Int, int64(2)},
+		{"string", c, "type", datamodel.Kind_String, "article"},
+		{"number", c, "version", datamodel.Kind_Int, int64(1)},
+		{"array", c, "tags", datamodel.Kind_List, nil},
+		{"link", c, "holder", datamodel.Kind_Link, cidlink.Link{Cid: e.Cid()}},
+		{"bytes", c, "raw", datamodel.Kind_Bytes, []byte{1}},
+		{"kernel context", k, "context", datamodel.Kind_Int, int64(1)},
+		{"kernel id", k, "id", datamodel.Kind_Bytes, make([]byte, 32)},
+		{"kernel number", k, "version", datamodel.Kind_Int, int64(1)},
+		{"kernel link", k, "content", datamodel.Kind_Link, cidlink.Link{Cid: c.Cid()}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			n, err := prime.NewNode(test.obj)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			value, err := n.LookupByString(test.key)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if value.Kind() != test.kind {
+				t.Fatalf("%s is expected but %s is found", test.kind, value.Kind())
+			}
+
+			var v interface{}
+			switch test.kind {
+			case datamodel.Kind_Int:
+				v, err = value.AsInt()
+			case datamodel.Kind_String:
+				v, err = value.AsString()
+			case datamodel.Kind_Link:
+				v, err = value.AsLink()
+			case datamodel.Kind_Bytes:
+				v, err = value.AsBytes()
+			default:
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !reflect.DeepEqual(v, test.value) {
+				t.Fatalf("%v is expected but %v is found", test.value, v)
+			}
+		})
+	}
+
+	n, err := prime.NewNode(c)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := n.LookupByString("missing"); err == nil {
+		t.Fatal("an error is expected for a missing key")
+	}
+}
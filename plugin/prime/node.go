@@ -0,0 +1,293 @@
+package prime
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strconv"
+
+	"github.com/ipfs/go-cid"
+	"github.com/ipld/go-ipld-prime/datamodel"
+	"github.com/ipld/go-ipld-prime/linking/cid"
+	"github.com/ipld/go-ipld-prime/node/basicnode"
+	"github.com/ipld/go-ipld-prime/node/mixins"
+	"github.com/likecoin/iscn-ipld/plugin/block"
+)
+
+// ==================================================
+// objectNode
+// ==================================================
+
+// objectNode exposes an ISCN object as a map node of ipld-prime
+type objectNode struct {
+	*mapNode
+
+	obj block.IscnObject
+}
+
+var _ datamodel.Node = (*objectNode)(nil)
+
+// NewNode creates an ipld-prime node of the ISCN object. The properties are
+// exposed as the IPLD data model of the object, i.e. as they are stored: the
+// context is the schema version, the links are Cid links, the numbers are
+// integers and the bytes, e.g. the ID of the kernel, are bytes
+func NewNode(obj block.IscnObject) (datamodel.Node, error) {
+	data, err := obj.ToDataModel()
+	if err != nil {
+		return nil, err
+	}
+
+	n := newMapNode(data)
+	n.TypeName = obj.GetName()
+	return &objectNode{
+		mapNode: n,
+		obj:     obj,
+	}, nil
+}
+
+// Object returns the ISCN object under the node
+func (n *objectNode) Object() block.IscnObject {
+	return n.obj
+}
+
+// ==================================================
+// mapNode
+// ==================================================
+
+// mapNode exposes a plain map as a map node of ipld-prime
+type mapNode struct {
+	mixins.Map
+
+	m    map[string]interface{}
+	keys []string
+}
+
+var _ datamodel.Node = (*mapNode)(nil)
+
+func newMapNode(m map[string]interface{}) *mapNode {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	return &mapNode{
+		m:    m,
+		keys: keys,
+	}
+}
+
+// LookupByString looks up the value of 'key'
+func (n *mapNode) LookupByString(key string) (datamodel.Node, error) {
+	value, ok := n.m[key]
+	if !ok {
+		return nil, datamodel.ErrNotExists{Segment: datamodel.PathSegmentOfString(key)}
+	}
+
+	return newValueNode(value)
+}
+
+// LookupByNode looks up the value of the string 'key' node
+func (n *mapNode) LookupByNode(key datamodel.Node) (datamodel.Node, error) {
+	s, err := key.AsString()
+	if err != nil {
+		return nil, err
+	}
+
+	return n.LookupByString(s)
+}
+
+// LookupBySegment looks up the value of the path segment
+func (n *mapNode) LookupBySegment(seg datamodel.PathSegment) (datamodel.Node, error) {
+	return n.LookupByString(seg.String())
+}
+
+// MapIterator returns an iterator over the map in sorted order of keys
+func (n *mapNode) MapIterator() datamodel.MapIterator {
+	return &mapIterator{
+		keys:   n.keys,
+		lookup: n.LookupByString,
+	}
+}
+
+// Length returns the number of entries
+func (n *mapNode) Length() int64 {
+	return int64(len(n.keys))
+}
+
+// Prototype returns the prototype of a basic map node
+func (n *mapNode) Prototype() datamodel.NodePrototype {
+	return basicnode.Prototype.Map
+}
+
+// mapIterator iterates over the keys of a map node
+type mapIterator struct {
+	keys   []string
+	lookup func(string) (datamodel.Node, error)
+	index  int
+}
+
+// Next returns the next entry of the map
+func (it *mapIterator) Next() (datamodel.Node, datamodel.Node, error) {
+	if it.Done() {
+		return nil, nil, datamodel.ErrIteratorOverread{}
+	}
+
+	key := it.keys[it.index]
+	it.index++
+
+	value, err := it.lookup(key)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return basicnode.NewString(key), value, nil
+}
+
+// Done checks whether all entries are iterated
+func (it *mapIterator) Done() bool {
+	return it.index >= len(it.keys)
+}
+
+// ==================================================
+// listNode
+// ==================================================
+
+// listNode exposes a plain array as a list node of ipld-prime
+type listNode struct {
+	mixins.List
+
+	array []interface{}
+}
+
+var _ datamodel.Node = (*listNode)(nil)
+
+// LookupByIndex looks up the element of 'idx'
+func (n *listNode) LookupByIndex(idx int64) (datamodel.Node, error) {
+	if idx < 0 || idx >= int64(len(n.array)) {
+		return nil, datamodel.ErrNotExists{Segment: datamodel.PathSegmentOfInt(idx)}
+	}
+
+	return newValueNode(n.array[idx])
+}
+
+// LookupBySegment looks up the element of the path segment
+func (n *listNode) LookupBySegment(seg datamodel.PathSegment) (datamodel.Node, error) {
+	idx, err := seg.Index()
+	if err != nil {
+		return nil, datamodel.ErrInvalidSegmentForList{TroubleSegment: seg, Reason: err}
+	}
+
+	return n.LookupByIndex(idx)
+}
+
+// ListIterator returns an iterator over the array
+func (n *listNode) ListIterator() datamodel.ListIterator {
+	return &listIterator{array: n.array}
+}
+
+// Length returns the number of elements
+func (n *listNode) Length() int64 {
+	return int64(len(n.array))
+}
+
+// Prototype returns the prototype of a basic list node
+func (n *listNode) Prototype() datamodel.NodePrototype {
+	return basicnode.Prototype.List
+}
+
+// listIterator iterates over the elements of a list node
+type listIterator struct {
+	array []interface{}
+	index int
+}
+
+// Next returns the next element of the array
+func (it *listIterator) Next() (int64, datamodel.Node, error) {
+	if it.Done() {
+		return -1, nil, datamodel.ErrIteratorOverread{}
+	}
+
+	idx := it.index
+	it.index++
+
+	value, err := newValueNode(it.array[idx])
+	if err != nil {
+		return -1, nil, err
+	}
+
+	return int64(idx), value, nil
+}
+
+// Done checks whether all elements are iterated
+func (it *listIterator) Done() bool {
+	return it.index >= len(it.array)
+}
+
+// ==================================================
+// value
+// ==================================================
+
+// newValueNode creates a node of the value in the data model of an ISCN object
+func newValueNode(value interface{}) (datamodel.Node, error) {
+	switch v := value.(type) {
+	case nil:
+		return datamodel.Null, nil
+	case cid.Cid:
+		return basicnode.NewLink(cidlink.Link{Cid: v}), nil
+	case map[string]interface{}:
+		return newMapNode(v), nil
+	case []interface{}:
+		return &listNode{array: v}, nil
+	case []byte:
+		return basicnode.NewBytes(v), nil
+	case string:
+		return basicnode.NewString(v), nil
+	case bool:
+		return basicnode.NewBool(v), nil
+	case float32:
+		return basicnode.NewFloat(float64(v)), nil
+	case float64:
+		return basicnode.NewFloat(v), nil
+	case uint:
+		return newUintNode(uint64(v))
+	case uint64:
+		return newUintNode(v)
+	}
+
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return basicnode.NewInt(v.Int()), nil
+	case reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return basicnode.NewInt(int64(v.Uint())), nil
+	case reflect.Map:
+		if v.Type().Key().Kind() != reflect.String {
+			break
+		}
+
+		m := map[string]interface{}{}
+		for _, k := range v.MapKeys() {
+			m[k.String()] = v.MapIndex(k).Interface()
+		}
+		return newMapNode(m), nil
+	case reflect.Slice, reflect.Array:
+		array := make([]interface{}, 0, v.Len())
+		for i := 0; i < v.Len(); i++ {
+			array = append(array, v.Index(i).Interface())
+		}
+		return &listNode{array: array}, nil
+	}
+
+	return nil, fmt.Errorf("Prime: '%T' is not supported", value)
+}
+
+// newUintNode creates an int node as long as the number fits in int64
+func newUintNode(u uint64) (datamodel.Node, error) {
+	if u > math.MaxInt64 {
+		return nil, fmt.Errorf("Prime: %s overflows int64", strconv.FormatUint(u, 10))
+	}
+
+	return basicnode.NewInt(int64(u)), nil
+}
@@ -0,0 +1,419 @@
+package printer
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/ipfs/go-cid"
+	"github.com/likecoin/iscn-ipld/plugin/block"
+
+	blocks "github.com/ipfs/go-block-format"
+)
+
+// BlockGetter is the interface to retrieve blocks, e.g. a blockstore
+type BlockGetter interface {
+	Get(cid.Cid) (blocks.Block, error)
+}
+
+// Format is a enum type for the output format
+type Format int
+
+const (
+	// FormatText represents plain text with indentation
+	FormatText Format = iota
+
+	// FormatMarkdown represents Markdown with nested lists
+	FormatMarkdown
+)
+
+// Option is an option for printing an ISCN record
+type Option func(*printer)
+
+// WithDepth sets how many levels of ISCN kernels linked as parent or footprint
+// are expanded, all are expanded if 'depth' is -1. Only the CIDs of the linked
+// kernels are printed by default
+func WithDepth(depth int) Option {
+	return func(p *printer) {
+		p.depth = depth
+	}
+}
+
+// WithFormat sets the output format, the default format is FormatText
+func WithFormat(format Format) Option {
+	return func(p *printer) {
+		p.format = format
+	}
+}
+
+// Print prints the ISCN record of the kernel 'root' as a tree: the kernel, its
+// content, stakeholders with the names of entities and rights
+func Print(out io.Writer, getter BlockGetter, root cid.Cid, opts ...Option) error {
+	p := &printer{
+		getter:   getter,
+		entities: map[cid.Cid]string{},
+	}
+	for _, opt := range opts {
+		opt(p)
+	}
+
+	tree, err := p.kernel(root, 0)
+	if err != nil {
+		return err
+	}
+
+	buf := &bytes.Buffer{}
+	p.render(buf, tree, 0)
+
+	_, err = out.Write(buf.Bytes())
+	return err
+}
+
+// Sprint prints the ISCN record of the kernel 'root' to a string
+func Sprint(getter BlockGetter, root cid.Cid, opts ...Option) (string, error) {
+	buf := &bytes.Buffer{}
+	if err := Print(buf, getter, root, opts...); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// ==================================================
+// line
+// ==================================================
+
+// line is a line of the output with the nested lines
+type line struct {
+	label    string
+	value    string
+	isCode   bool
+	children []*line
+}
+
+// add adds a nested line and returns it
+func (l *line) add(label string, value string) *line {
+	child := &line{
+		label: label,
+		value: value,
+	}
+	l.children = append(l.children, child)
+	return child
+}
+
+// addCode adds a nested line of which the value is code, e.g. CID
+func (l *line) addCode(label string, value string) *line {
+	child := l.add(label, value)
+	child.isCode = true
+	return child
+}
+
+// ==================================================
+// printer
+// ==================================================
+
+// printer builds the tree of an ISCN record
+type printer struct {
+	getter BlockGetter
+	depth  int
+	format Format
+
+	entities map[cid.Cid]string
+}
+
+// render writes the line and the nested lines in the output format
+func (p *printer) render(buf *bytes.Buffer, l *line, level int) {
+	value := l.value
+	if p.format == FormatMarkdown {
+		if l.isCode && value != "" {
+			value = "`" + value + "`"
+		} else {
+			value = markdownEscaper.Replace(value)
+		}
+
+		buf.WriteString(strings.Repeat("  ", level))
+		buf.WriteString("- **" + markdownEscaper.Replace(l.label) + "**")
+	} else {
+		buf.WriteString(strings.Repeat("  ", level))
+		buf.WriteString(l.label)
+	}
+
+	if value != "" {
+		buf.WriteString(": " + value)
+	}
+	buf.WriteString("\n")
+
+	for _, child := range l.children {
+		p.render(buf, child, level+1)
+	}
+}
+
+// markdownEscaper escapes the Markdown syntax in the text of a line, e.g. the
+// names of entities and the titles, and the line breaks are kept as <br>
+var markdownEscaper = strings.NewReplacer(
+	`\`, `\\`,
+	"`", "\\`",
+	`*`, `\*`,
+	`_`, `\_`,
+	`[`, `\[`,
+	`]`, `\]`,
+	`<`, `\<`,
+	`>`, `\>`,
+	`|`, `\|`,
+	`~`, `\~`,
+	`&`, `\&`,
+	`#`, `\#`,
+	"\r\n", "<br>",
+	"\n", "<br>",
+	"\r", "<br>",
+)
+
+// decode retrieves the block 'c' and decodes it as data model
+func (p *printer) decode(c cid.Cid) (block.IscnObject, map[string]interface{}, error) {
+	blk, err := p.getter.Get(c)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	obj, err := block.Decode(blk.RawData(), c)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	data, err := obj.ToDataModel()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return obj, data, nil
+}
+
+// kernel builds the tree of the ISCN kernel 'c' at level 'depth'
+func (p *printer) kernel(c cid.Cid, depth int) (*line, error) {
+	obj, data, err := p.decode(c)
+	if err != nil {
+		return nil, err
+	}
+
+	id, _, err := obj.Resolve([]string{"id"})
+	if err != nil {
+		return nil, err
+	}
+
+	res := &line{
+		label:  "ISCN",
+		value:  fmt.Sprint(id),
+		isCode: true,
+	}
+	res.addCode("CID", c.String())
+	res.add("Timestamp", valueOf(data["timestamp"]))
+	res.add("Version", valueOf(data["version"]))
+
+	if parent, ok := data["parent"].(cid.Cid); ok {
+		if err := p.linkedKernel(res, "Parent", parent, depth); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := p.content(res, data["content"]); err != nil {
+		return nil, err
+	}
+
+	if err := p.stakeholders(res, data["stakeholders"], depth); err != nil {
+		return nil, err
+	}
+
+	if err := p.rights(res, data["rights"]); err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// linkedKernel adds the kernel linked as parent or footprint, it is expanded
+// if the depth is not reached
+func (p *printer) linkedKernel(l *line, label string, c cid.Cid, depth int) error {
+	if p.depth >= 0 && depth+1 > p.depth {
+		l.addCode(label, c.String())
+		return nil
+	}
+
+	tree, err := p.kernel(c, depth+1)
+	if err != nil {
+		return err
+	}
+
+	tree.label = label + " " + tree.label
+	l.children = append(l.children, tree)
+	return nil
+}
+
+// content adds the content linked by the kernel
+func (p *printer) content(l *line, link interface{}) error {
+	c, ok := link.(cid.Cid)
+	if !ok {
+		return fmt.Errorf("Printer: content is not found")
+	}
+
+	_, data, err := p.decode(c)
+	if err != nil {
+		return err
+	}
+
+	res := l.add("Content", valueOf(data["title"]))
+	res.addCode("CID", c.String())
+	for _, key := range []string{"type", "version", "edition", "source", "fingerprint", "description"} {
+		if value, ok := data[key]; ok {
+			res.add(labelOf(key), valueOf(value))
+		}
+	}
+
+	if tags, ok := data["tags"].([]interface{}); ok && len(tags) > 0 {
+		values := []string{}
+		for _, tag := range tags {
+			values = append(values, valueOf(tag))
+		}
+		res.add("Tags", strings.Join(values, ", "))
+	}
+
+	return nil
+}
+
+// stakeholders adds the stakeholders linked by the kernel
+func (p *printer) stakeholders(l *line, link interface{}, depth int) error {
+	c, ok := link.(cid.Cid)
+	if !ok {
+		return fmt.Errorf("Printer: stakeholders are not found")
+	}
+
+	_, data, err := p.decode(c)
+	if err != nil {
+		return err
+	}
+
+	res := l.add("Stakeholders", "")
+	res.addCode("CID", c.String())
+
+	stakeholders, _ := data["stakeholders"].([]interface{})
+	for _, value := range stakeholders {
+		stakeholder, ok := value.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		s := res.add(valueOf(stakeholder["type"]), p.entity(stakeholder["stakeholder"]))
+		s.add("Sharing", valueOf(stakeholder["sharing"]))
+
+		switch footprint := stakeholder["footprint"].(type) {
+		case cid.Cid:
+			if err := p.linkedKernel(s, "Footprint", footprint, depth); err != nil {
+				return err
+			}
+		case string:
+			s.add("Footprint", footprint)
+		}
+	}
+
+	return nil
+}
+
+// rights adds the rights linked by the kernel
+func (p *printer) rights(l *line, link interface{}) error {
+	c, ok := link.(cid.Cid)
+	if !ok {
+		return fmt.Errorf("Printer: rights are not found")
+	}
+
+	_, data, err := p.decode(c)
+	if err != nil {
+		return err
+	}
+
+	res := l.add("Rights", "")
+	res.addCode("CID", c.String())
+
+	rights, _ := data["rights"].([]interface{})
+	for _, value := range rights {
+		right, ok := value.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		r := res.add(valueOf(right["type"]), "")
+		r.add("Holder", p.entity(right["holder"]))
+		if terms, ok := right["terms"].(cid.Cid); ok {
+			r.addCode("Terms", terms.String())
+		}
+
+		if period, ok := right["period"].(map[string]interface{}); ok {
+			r.add("Period", periodOf(period))
+		}
+
+		if territory, ok := right["territory"]; ok {
+			r.add("Territory", valueOf(territory))
+		}
+	}
+
+	return nil
+}
+
+// entity returns the name of the entity linked, or the CID if the entity
+// cannot be retrieved
+func (p *printer) entity(link interface{}) string {
+	c, ok := link.(cid.Cid)
+	if !ok {
+		return valueOf(link)
+	}
+
+	if name, ok := p.entities[c]; ok {
+		return name
+	}
+
+	name := c.String()
+	if _, data, err := p.decode(c); err == nil {
+		if value, ok := data["name"]; ok {
+			name = valueOf(value)
+			if id, ok := data["id"]; ok {
+				name = fmt.Sprintf("%s (%s)", name, valueOf(id))
+			}
+		}
+	}
+
+	p.entities[c] = name
+	return name
+}
+
+// labelOf converts a key to a label, e.g. "fingerprint" to "Fingerprint"
+func labelOf(key string) string {
+	if key == "" {
+		return key
+	}
+	return strings.ToUpper(key[:1]) + key[1:]
+}
+
+// valueOf converts a value in data model to a string
+func valueOf(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case cid.Cid:
+		return v.String()
+	}
+	return fmt.Sprint(value)
+}
+
+// periodOf converts a time period to a string
+func periodOf(period map[string]interface{}) string {
+	from, hasFrom := period["from"]
+	to, hasTo := period["to"]
+
+	switch {
+	case hasFrom && hasTo:
+		return fmt.Sprintf("%s to %s", valueOf(from), valueOf(to))
+	case hasFrom:
+		return fmt.Sprintf("from %s", valueOf(from))
+	case hasTo:
+		return fmt.Sprintf("until %s", valueOf(to))
+	}
+	return ""
+}
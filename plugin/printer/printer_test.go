@@ -0,0 +1,177 @@
+package printer_test
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/ipfs/go-cid"
+	"github.com/likecoin/iscn-ipld/plugin/block"
+	"github.com/likecoin/iscn-ipld/plugin/internal/blocktest"
+	"github.com/likecoin/iscn-ipld/plugin/printer"
+
+	blocks "github.com/ipfs/go-block-format"
+)
+
+// store is a blockstore in memory
+type store map[cid.Cid]blocks.Block
+
+func (s store) Get(c cid.Cid) (blocks.Block, error) {
+	blk, ok := s[c]
+	if !ok {
+		return nil, fmt.Errorf("Cid %q is not found", c.String())
+	}
+	return blk, nil
+}
+
+func TestMain(m *testing.M) {
+	blocktest.Register()
+	os.Exit(m.Run())
+}
+
+// record creates an ISCN record with the texts in a store and returns the
+// kernel
+func record(t *testing.T, name string, title string, tag string, territory string) (store, cid.Cid) {
+	t.Helper()
+
+	s := store{}
+	put := func(codec uint64, version uint64, data map[string]interface{}) cid.Cid {
+		obj, err := block.Encode(codec, version, data)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		blk, err := blocks.NewBlockWithCid(obj.RawData(), obj.Cid())
+		if err != nil {
+			t.Fatal(err)
+		}
+		s[blk.Cid()] = blk
+		return obj.Cid()
+	}
+
+	holder := put(block.CodecEntity, 1, map[string]interface{}{"id": "llc://holder", "name": name})
+	return s, put(block.CodecISCN, 2, map[string]interface{}{
+		"id":        make([]byte, 32),
+		"timestamp": "2020-01-01T00:00:00Z",
+		"version":   1,
+		"rights": put(block.CodecRights, 2, map[string]interface{}{
+			"rights": []interface{}{
+				map[string]interface{}{
+					"holder":    holder,
+					"type":      "License",
+					"terms":     holder,
+					"territory": territory,
+				},
+			},
+		}),
+		"stakeholders": put(block.CodecStakeholders, 2, map[string]interface{}{
+			"stakeholders": []interface{}{
+				map[string]interface{}{"type": "Creator", "stakeholder": holder, "sharing": 100},
+			},
+		}),
+		"content": put(block.CodecContent, 2, map[string]interface{}{
+			"type":        "article",
+			"version":     1,
+			"fingerprint": "hash://sha256/abc",
+			"title":       title,
+			"tags":        []string{tag},
+		}),
+	})
+}
+
+func TestPrintMarkdownEscape(t *testing.T) {
+	tests := []struct {
+		name     string
+		text     string
+		markdown string
+	}{
+		{"plain", "Alice", "Alice"},
+		{"emphasis", "*Alice* _Bob_", `\*Alice\* \_Bob\_`},
+		{"table", "a | b", `a \| b`},
+		{"link", "[a](http://x)", `\[a\](http://x)`},
+		{"html", "<b>&amp;</b>", `\<b\>\&amp;\</b\>`},
+		{"code", "`a`", "\\`a\\`"},
+		{"backslash", `a\*`, `a\\\*`},
+		{"heading", "# a", `\# a`},
+		{"newline", "a\nb\r\nc", "a<br>b<br>c"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s, root := record(t, test.text, test.text, test.text, test.text)
+
+			out, err := printer.Sprint(s, root, printer.WithFormat(printer.FormatMarkdown))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			for _, expected := range []string{
+				"- **Content**: " + test.markdown + "\n",
+				"- **Tags**: " + test.markdown + "\n",
+				"- **Creator**: " + test.markdown + " (llc://holder)\n",
+				"- **Holder**: " + test.markdown + " (llc://holder)\n",
+				"- **Territory**: " + test.markdown + "\n",
+			} {
+				if !strings.Contains(out, expected) {
+					t.Fatalf("%q is expected in\n%s", expected, out)
+				}
+			}
+
+			out, err = printer.Sprint(s, root)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			expected := "Content: " + test.text + "\n"
+			if !strings.Contains(out, expected) {
+				t.Fatalf("%q is expected in\n%s", expected, out)
+			}
+		})
+	}
+}
+
+func TestPrintFormats(t *testing.T) {
+	s, root := record(t, "Alice", "Title", "tag", "Global")
+
+	tests := []struct {
+		name     string
+		opts     []printer.Option
+		expected []string
+	}{
+		{"text", nil, []string{
+			"ISCN: 1/",
+			"  CID: " + root.String() + "\n",
+			"  Content: Title\n",
+			"    Tags: tag\n",
+			"  Stakeholders\n",
+			"    Creator: Alice (llc://holder)\n",
+			"      Sharing: 100\n",
+			"  Rights\n",
+			"    License\n",
+			"      Territory: Global\n",
+		}},
+		{"markdown", []printer.Option{printer.WithFormat(printer.FormatMarkdown)}, []string{
+			"- **ISCN**: `1/",
+			"  - **CID**: `" + root.String() + "`\n",
+			"  - **Content**: Title\n",
+			"  - **Stakeholders**\n",
+			"    - **Creator**: Alice (llc://holder)\n",
+		}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			out, err := printer.Sprint(s, root, test.opts...)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			for _, expected := range test.expected {
+				if !strings.Contains(out, expected) {
+					t.Fatalf("%q is expected in\n%s", expected, out)
+				}
+			}
+		})
+	}
+}